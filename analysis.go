@@ -0,0 +1,378 @@
+package search
+
+import (
+	"fmt"
+)
+
+// Schema describes the analysis and field mappings an index is created with.
+// Engines apply the schema when the index is setup, after which analyzers
+// can be referenced by name from field mappings and match queries.
+type Schema struct {
+	Analyzers []Analyzer
+	Fields    []FieldMapping
+}
+
+// Analyzer defines a named text analysis chain. Text is first run through
+// the char filters, then split into tokens by the tokenizer, and finally
+// run through each of the token filters in order.
+type Analyzer struct {
+	Name         string
+	CharFilters  []CharFilter
+	Tokenizer    Tokenizer
+	TokenFilters []TokenFilter
+}
+
+func NewAnalyzer(name string, tokenizer Tokenizer) *Analyzer {
+	return &Analyzer{
+		Name:      name,
+		Tokenizer: tokenizer,
+	}
+}
+
+func (a *Analyzer) AddCharFilters(filters ...CharFilter) *Analyzer {
+	a.CharFilters = append(a.CharFilters, filters...)
+	return a
+}
+
+func (a *Analyzer) AddTokenFilters(filters ...TokenFilter) *Analyzer {
+	a.TokenFilters = append(a.TokenFilters, filters...)
+	return a
+}
+
+type CharFilterType int
+
+func (c CharFilterType) String() string {
+	if int(c) >= len(charFilterTypes) {
+		return "unknown char filter type"
+	}
+	return charFilterTypes[c] + " char filter type"
+}
+
+const (
+	CharFilterTypeUnknown CharFilterType = iota
+	CharFilterTypeHTML
+	CharFilterTypeRegexp
+	CharFilterTypeZeroWidth
+)
+
+var charFilterTypes = [...]string{
+	CharFilterTypeUnknown:   "unknown",
+	CharFilterTypeHTML:      "html",
+	CharFilterTypeRegexp:    "regexp",
+	CharFilterTypeZeroWidth: "zero width",
+}
+
+type CharFilter struct {
+	Type    CharFilterType
+	Regexp  string
+	Replace string
+}
+
+// NewCharFilterHTML strips html markup from the text.
+func NewCharFilterHTML() CharFilter {
+	return CharFilter{Type: CharFilterTypeHTML}
+}
+
+// NewCharFilterRegexp replaces all matches of the regexp with the replacement.
+func NewCharFilterRegexp(regexp, replace string) CharFilter {
+	return CharFilter{
+		Type:    CharFilterTypeRegexp,
+		Regexp:  regexp,
+		Replace: replace,
+	}
+}
+
+// NewCharFilterZeroWidth replaces zero width non-joiners with spaces.
+func NewCharFilterZeroWidth() CharFilter {
+	return CharFilter{Type: CharFilterTypeZeroWidth}
+}
+
+type TokenizerType int
+
+func (t TokenizerType) String() string {
+	if int(t) >= len(tokenizerTypes) {
+		return "unknown tokenizer type"
+	}
+	return tokenizerTypes[t] + " tokenizer type"
+}
+
+const (
+	TokenizerTypeUnknown TokenizerType = iota
+	TokenizerTypeKeyword
+	TokenizerTypeLetter
+	TokenizerTypeRegexp
+	TokenizerTypeUnicode
+	TokenizerTypeWhitespace
+)
+
+var tokenizerTypes = [...]string{
+	TokenizerTypeUnknown:    "unknown",
+	TokenizerTypeKeyword:    "keyword",
+	TokenizerTypeLetter:     "letter",
+	TokenizerTypeRegexp:     "regexp",
+	TokenizerTypeUnicode:    "unicode",
+	TokenizerTypeWhitespace: "whitespace",
+}
+
+type Tokenizer struct {
+	Type   TokenizerType
+	Regexp string
+}
+
+// NewTokenizerKeyword emits the entire input as a single token.
+func NewTokenizerKeyword() Tokenizer {
+	return Tokenizer{Type: TokenizerTypeKeyword}
+}
+
+// NewTokenizerLetter splits on anything that is not a letter.
+func NewTokenizerLetter() Tokenizer {
+	return Tokenizer{Type: TokenizerTypeLetter}
+}
+
+// NewTokenizerRegexp emits every match of the regexp as a token.
+func NewTokenizerRegexp(regexp string) Tokenizer {
+	return Tokenizer{
+		Type:   TokenizerTypeRegexp,
+		Regexp: regexp,
+	}
+}
+
+// NewTokenizerUnicode splits on unicode word boundaries.
+func NewTokenizerUnicode() Tokenizer {
+	return Tokenizer{Type: TokenizerTypeUnicode}
+}
+
+// NewTokenizerWhitespace splits on whitespace.
+func NewTokenizerWhitespace() Tokenizer {
+	return Tokenizer{Type: TokenizerTypeWhitespace}
+}
+
+type TokenFilterType int
+
+func (t TokenFilterType) String() string {
+	if int(t) >= len(tokenFilterTypes) {
+		return "unknown token filter type"
+	}
+	return tokenFilterTypes[t] + " token filter type"
+}
+
+const (
+	TokenFilterTypeUnknown TokenFilterType = iota
+	TokenFilterTypeASCIIFolding
+	TokenFilterTypeEdgeNgram
+	TokenFilterTypeLowercase
+	TokenFilterTypeNgram
+	TokenFilterTypeStemmer
+	TokenFilterTypeStop
+	TokenFilterTypeSynonym
+)
+
+var tokenFilterTypes = [...]string{
+	TokenFilterTypeUnknown:      "unknown",
+	TokenFilterTypeASCIIFolding: "ascii folding",
+	TokenFilterTypeEdgeNgram:    "edge ngram",
+	TokenFilterTypeLowercase:    "lowercase",
+	TokenFilterTypeNgram:        "ngram",
+	TokenFilterTypeStemmer:      "stemmer",
+	TokenFilterTypeStop:         "stop",
+	TokenFilterTypeSynonym:      "synonym",
+}
+
+type TokenFilter struct {
+	Type     TokenFilterType
+	Language Language
+	Words    []string
+	Min, Max int
+	Back     bool
+	Synonyms *Synonyms
+}
+
+// NewTokenFilterASCIIFolding folds letters with diacritics, ligatures and
+// the like into their closest ascii equivalent, i.e. é becomes e.
+func NewTokenFilterASCIIFolding() TokenFilter {
+	return TokenFilter{Type: TokenFilterTypeASCIIFolding}
+}
+
+// NewTokenFilterEdgeNgram emits the ngrams anchored at the start of each
+// token, i.e. shoe becomes s, sh, sho and shoe for min 1 and max 4.
+func NewTokenFilterEdgeNgram(min, max int) TokenFilter {
+	return TokenFilter{
+		Type: TokenFilterTypeEdgeNgram,
+		Min:  min,
+		Max:  max,
+	}
+}
+
+func NewTokenFilterLowercase() TokenFilter {
+	return TokenFilter{Type: TokenFilterTypeLowercase}
+}
+
+// NewTokenFilterNgram emits every ngram of each token between min and max
+// characters long.
+func NewTokenFilterNgram(min, max int) TokenFilter {
+	return TokenFilter{
+		Type: TokenFilterTypeNgram,
+		Min:  min,
+		Max:  max,
+	}
+}
+
+// NewTokenFilterStemmer reduces each token to its stem using the stemmer
+// of the provided language.
+func NewTokenFilterStemmer(lang Language) TokenFilter {
+	return TokenFilter{
+		Type:     TokenFilterTypeStemmer,
+		Language: lang,
+	}
+}
+
+// NewTokenFilterStop removes the stop words from the token stream.
+func NewTokenFilterStop(words ...string) TokenFilter {
+	return TokenFilter{
+		Type:  TokenFilterTypeStop,
+		Words: words,
+	}
+}
+
+// NewTokenFilterStopLanguage removes the stop words of the provided
+// language from the token stream.
+func NewTokenFilterStopLanguage(lang Language) TokenFilter {
+	return TokenFilter{
+		Type:     TokenFilterTypeStop,
+		Language: lang,
+	}
+}
+
+// NewTokenFilterSynonyms applies the synonyms to the token stream. Synonym
+// terms are lowercased, so the filter should follow a lowercase filter.
+func NewTokenFilterSynonyms(syn *Synonyms) TokenFilter {
+	return TokenFilter{
+		Type:     TokenFilterTypeSynonym,
+		Synonyms: syn,
+	}
+}
+
+type Language string
+
+const (
+	LanguageEnglish  Language = "en"
+	LanguageFrench   Language = "fr"
+	LanguageGerman   Language = "de"
+	LanguageJapanese Language = "ja"
+)
+
+type FieldType int
+
+func (f FieldType) String() string {
+	if int(f) >= len(fieldTypes) {
+		return "unknown field type"
+	}
+	return fieldTypes[f] + " field type"
+}
+
+const (
+	FieldTypeText FieldType = iota
+	FieldTypeBoolean
+	FieldTypeDateTime
+	FieldTypeNumeric
+	FieldTypeGeoPoint
+	FieldTypeCompletion
+)
+
+var fieldTypes = [...]string{
+	FieldTypeText:       "text",
+	FieldTypeBoolean:    "boolean",
+	FieldTypeDateTime:   "datetime",
+	FieldTypeNumeric:    "numeric",
+	FieldTypeGeoPoint:   "geo point",
+	FieldTypeCompletion: "completion",
+}
+
+// FieldMapping explicitly maps a document field. Nested fields are addressed
+// by their dotted path, i.e. nest.first. Fields that are not mapped are
+// still indexed with the engine defaults.
+type FieldMapping struct {
+	Path     string
+	Type     FieldType
+	Analyzer string
+
+	// Language selects the engine's analyzer preset for the language.
+	Language Language
+
+	// Languages enables language detection at index time. The text of the
+	// field is indexed into the variant of the field for the detected
+	// language, see LanguageField, which is analyzed with the preset of that
+	// language.
+	Languages []Language
+
+	// Weight is the path of the numeric field holding the weight of the
+	// suggestions of a completion field. Suggestions with a higher weight
+	// rank first.
+	Weight string
+}
+
+func NewFieldMapping(path string, typ FieldType) *FieldMapping {
+	return &FieldMapping{
+		Path: path,
+		Type: typ,
+	}
+}
+
+func (f *FieldMapping) SetAnalyzer(analyzer string) *FieldMapping {
+	f.Analyzer = analyzer
+	return f
+}
+
+func (f *FieldMapping) SetLanguage(lang Language) *FieldMapping {
+	f.Language = lang
+	return f
+}
+
+func (f *FieldMapping) SetLanguages(langs ...Language) *FieldMapping {
+	f.Languages = langs
+	return f
+}
+
+func (f *FieldMapping) SetWeight(path string) *FieldMapping {
+	f.Weight = path
+	return f
+}
+
+// Validate verifies the analyzers and field mappings are well formed.
+func (s Schema) Validate() error {
+	analyzers := make(map[string]bool)
+	for _, a := range s.Analyzers {
+		if a.Name == "" {
+			return fmt.Errorf("analyzer must have a name")
+		}
+		if analyzers[a.Name] {
+			return fmt.Errorf("analyzer %q is defined more than once", a.Name)
+		}
+		if a.Tokenizer.Type == TokenizerTypeUnknown {
+			return fmt.Errorf("analyzer %q must have a tokenizer", a.Name)
+		}
+		analyzers[a.Name] = true
+	}
+
+	for _, f := range s.Fields {
+		if f.Path == "" {
+			return fmt.Errorf("field mapping must have a path")
+		}
+		analysisOpts := 0
+		for _, set := range []bool{f.Analyzer != "", f.Language != "", len(f.Languages) > 0} {
+			if set {
+				analysisOpts++
+			}
+		}
+		if analysisOpts > 0 && f.Type != FieldTypeText {
+			return fmt.Errorf("field %q: analysis provided for %s", f.Path, f.Type)
+		}
+		if f.Weight != "" && f.Type != FieldTypeCompletion {
+			return fmt.Errorf("field %q: weight provided for %s", f.Path, f.Type)
+		}
+		if analysisOpts > 1 {
+			return fmt.Errorf("field %q: only one of analyzer, language or languages may be provided", f.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"context"
+	"errors"
+)
+
+// Cursor iterates over every hit of a query. Hits are fetched lazily a page
+// at a time, each page searching after the sort values of the last hit of
+// the previous page. The sort of the search is completed with a tiebreaker
+// on the document ID so every hit is returned exactly once.
+type Cursor struct {
+	ctx   context.Context
+	index Index
+	query Query
+	req   SearchRequest
+
+	hits []Hit
+	hit  Hit
+	done bool
+	err  error
+}
+
+// NewCursor creates a cursor over the hits of the query. The size of the
+// search request is used as the page size and From is ignored.
+func NewCursor(ctx context.Context, index Index, q Query, opts ...SearchOptFn) *Cursor {
+	req := NewSearchRequest(opts...)
+	req.From, req.After = 0, nil
+	if req.Size <= 0 {
+		req.Size = defaultSearchSize
+	}
+	if len(req.Sort) == 0 {
+		req.Sort = []Sort{SortByScore()}
+	}
+	if req.Sort[len(req.Sort)-1].Type != SortTypeID {
+		req.Sort = append(req.Sort[:len(req.Sort):len(req.Sort)], SortByID())
+	}
+
+	c := &Cursor{
+		ctx:   ctx,
+		index: index,
+		query: q,
+		req:   req,
+	}
+	if req.Collapse != nil {
+		c.err = errors.New("collapsed results can not be iterated with a cursor")
+	}
+	return c
+}
+
+// Next advances the cursor to the next hit. It returns false when the hits
+// are exhausted or an error occurred, which is then returned by Err.
+func (c *Cursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	if err := c.ctx.Err(); err != nil {
+		c.err = err
+		return false
+	}
+
+	if len(c.hits) == 0 {
+		if c.done {
+			return false
+		}
+		if err := c.fetch(); err != nil {
+			c.err = err
+			return false
+		}
+		if len(c.hits) == 0 {
+			return false
+		}
+	}
+
+	c.hit, c.hits = c.hits[0], c.hits[1:]
+	return true
+}
+
+func (c *Cursor) fetch() error {
+	res, err := c.index.Search(c.ctx, c.query, func(r *SearchRequest) {
+		*r = c.req
+	})
+	if err != nil {
+		return err
+	}
+
+	c.hits = res.Hits
+	c.done = len(res.Hits) < c.req.Size
+	if len(res.Hits) > 0 {
+		c.req.After = res.Hits[len(res.Hits)-1].Sort
+	}
+	return nil
+}
+
+// Hit returns the current hit.
+func (c *Cursor) Hit() Hit {
+	return c.hit
+}
+
+// Err returns the error that stopped the iteration.
+func (c *Cursor) Err() error {
+	return c.err
+}
@@ -9,23 +9,62 @@ import (
 
 type (
 	Engine interface {
+		// Index returns the index with the name. The methods of the index
+		// returned for an unknown name fail with ErrIndexNotFound.
 		Index(name string) Index
+		// LookupIndex returns the index with the name or an error of kind
+		// ErrIndexNotFound when the engine has no such index.
+		LookupIndex(name string) (Index, error)
+		// Indices returns the indices of the engine sorted by name.
 		Indices() []Index
+		// CreateIndex creates an index with the schema. It fails with
+		// ErrIndexExists when the engine has an index with the name.
+		CreateIndex(ctx context.Context, name string, schema Schema) (Index, error)
+		// CloseIndex closes the index and removes it from the engine. The
+		// methods of the index fail once it is closed.
+		CloseIndex(ctx context.Context, name string) error
 	}
 
 	Index interface {
 		Name() string
 		Index(ctx context.Context, id string, data interface{}) error
-		Search(ctx context.Context, q Query) (*Result, error)
+		// IndexBatch indexes the documents in batches. Cancelling the
+		// context aborts the remaining batches, the documents of the
+		// batches already applied stay indexed.
+		IndexBatch(ctx context.Context, docs ...Document) error
+		Search(ctx context.Context, q Query, opts ...SearchOptFn) (*Result, error)
+		// Suggest completes the prefix from the values of a field mapped
+		// with the completion field type.
+		Suggest(ctx context.Context, field, prefix string, opts ...SuggestOptFn) ([]Suggestion, error)
+		// SuggestTerms proposes corrections for the terms of the text that
+		// are not in the field.
+		SuggestTerms(ctx context.Context, field, text string, opts ...TermSuggestOptFn) ([]TermSuggestion, error)
 	}
 )
 
+type Document struct {
+	ID   string
+	Data interface{}
+}
+
 type Result struct {
 	Status   *Status
 	Hits     []Hit
 	Total    uint64
 	MaxScore float64
 	Took     time.Duration
+
+	// Profile is only set when requested with WithProfile.
+	Profile *Profile
+
+	// Groups is the number of groups of collapsed results. Total remains the
+	// number of matching documents.
+	Groups uint64
+
+	// DidYouMean is the text of the query with its misspelled terms
+	// corrected. It is only set when requested with WithDidYouMean and a
+	// correction was found.
+	DidYouMean string
 }
 
 func (r *Result) String() string {
@@ -52,6 +91,17 @@ type Hit struct {
 	// SearchRequest.Fields. Text fields are returned as strings, numeric
 	// fields as float64s and date fields as time.RFC3339 formatted strings.
 	Fields map[string]interface{}
+
+	// Group is set on the hits of collapsed results.
+	Group *HitGroup
+}
+
+// HitGroup describes the group a collapsed hit is the top hit of. Total is
+// the number of matching documents in the group.
+type HitGroup struct {
+	Key       string
+	Total     uint64
+	InnerHits []Hit
 }
 
 type Explanation struct {
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of errors returned by the engines. Use errors.Is to check the
+// kind of an error.
+var (
+	ErrIndexNotFound      = errors.New("index not found")
+	ErrIndexExists        = errors.New("index already exists")
+	ErrDocumentNotFound   = errors.New("document not found")
+	ErrInvalidQuery       = errors.New("invalid query")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrMappingConflict    = errors.New("mapping conflict")
+	ErrBackendUnavailable = errors.New("backend unavailable")
+	ErrTimeout            = errors.New("timeout")
+)
+
+// Error is returned by the engines when an operation on an index fails.
+// Kind is one of the error kinds of this package, when known, and Err the
+// cause. Both are matched by errors.Is, so errors.Is(err, context.Canceled)
+// reports whether the operation was cancelled.
+type Error struct {
+	Op    string
+	Index string
+	Kind  error
+	Err   error
+}
+
+func (e *Error) Error() string {
+	msg := e.Op
+	if e.Index != "" {
+		msg += fmt.Sprintf(" %q", e.Index)
+	}
+	if e.Kind != nil {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
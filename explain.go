@@ -0,0 +1,36 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String renders the explanation as a tree indented by depth, one score per
+// line. The clauses of a sum are annotated with their share of the sum.
+func (e *Explanation) String() string {
+	var b strings.Builder
+	e.render(&b, 0, -1)
+	return b.String()
+}
+
+func (e *Explanation) render(b *strings.Builder, depth int, share float64) {
+	if e == nil {
+		return
+	}
+
+	b.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(b, "%.4f", e.Value)
+	if share >= 0 {
+		fmt.Fprintf(b, " (%.1f%%)", share*100)
+	}
+	b.WriteString(" " + e.Message + "\n")
+
+	sum := strings.HasPrefix(e.Message, "sum of") && e.Value != 0
+	for _, child := range e.Children {
+		childShare := -1.0
+		if sum && child != nil {
+			childShare = child.Value / e.Value
+		}
+		child.render(b, depth+1, childShare)
+	}
+}
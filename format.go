@@ -0,0 +1,226 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatQuery prints the plan of the query on a single line, such as
+// boolean(must=[match(field=text, matches=["phone"])], min_should=1)^2.
+// Options are printed in a fixed order and options left unset are omitted,
+// so equal plans print the same.
+func FormatQuery(q Query) string {
+	var b strings.Builder
+	formatQuery(&b, q)
+	return b.String()
+}
+
+func formatQuery(b *strings.Builder, q Query) {
+	if q == nil {
+		b.WriteString("nil")
+		return
+	}
+
+	plan := q.QueryPlan()
+	b.WriteString(plan.Type.Name())
+	b.WriteByte('(')
+
+	var opts []string
+	add := func(key, value string) {
+		opts = append(opts, key+"="+value)
+	}
+	addQueries := func(key string, queries []Query) {
+		if len(queries) == 0 {
+			return
+		}
+		parts := make([]string, 0, len(queries))
+		for _, child := range queries {
+			parts = append(parts, FormatQuery(child))
+		}
+		add(key, "["+strings.Join(parts, ", ")+"]")
+	}
+	addStrings := func(key string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		quoted := make([]string, 0, len(values))
+		for _, v := range values {
+			quoted = append(quoted, strconv.Quote(v))
+		}
+		add(key, "["+strings.Join(quoted, ", ")+"]")
+	}
+	addInt := func(key string, v int) {
+		if v != 0 {
+			add(key, strconv.Itoa(v))
+		}
+	}
+	addFloat := func(key string, v float64) {
+		if v != 0 {
+			add(key, formatFloat(v))
+		}
+	}
+
+	if plan.FieldVal != "" {
+		add("field", plan.FieldVal)
+	}
+	addStrings("fields", plan.Fields)
+	addStrings("matches", plan.Matches)
+	if len(plan.Terms) > 0 {
+		terms := make([]string, 0, len(plan.Terms))
+		for _, t := range plan.Terms {
+			terms = append(terms, strings.Join(t, "|"))
+		}
+		addStrings("terms", terms)
+	}
+	if plan.Type == QueryTypeBoolField {
+		add("bool", strconv.FormatBool(plan.Bool))
+	}
+	if plan.Analyzer != "" {
+		add("analyzer", plan.Analyzer)
+	}
+	switch plan.Operator {
+	case MatchQueryOperatorAnd:
+		add("operator", "and")
+	case MatchQueryOperatorPercent:
+		add("operator", "percent")
+	}
+	addInt("fuzziness", plan.Fuzziness)
+	addInt("prefix", plan.Prefix)
+	if plan.Transpositions {
+		add("transpositions", "true")
+	}
+	if boundSet(plan.Min) {
+		add(boundKey("min", plan.InclusiveMin), formatBound(plan.Min))
+	}
+	if boundSet(plan.Max) {
+		add(boundKey("max", plan.InclusiveMax), formatBound(plan.Max))
+	}
+	if len(plan.Points) > 0 {
+		points := make([]string, 0, len(plan.Points))
+		for _, p := range plan.Points {
+			points = append(points, formatFloat(p.Lat)+" "+formatFloat(p.Lon))
+		}
+		add("points", "["+strings.Join(points, ", ")+"]")
+	}
+	if plan.Distance != "" {
+		add("distance", plan.Distance)
+	}
+	if plan.Type == QueryTypeMultiMatch {
+		add("type", strings.TrimSuffix(plan.MultiMatch.String(), " multi match type"))
+	}
+
+	addQueries("must", plan.Must)
+	addQueries("should", plan.Should)
+	addQueries("must_not", plan.MustNot)
+	addQueries("filter", plan.Filter)
+	addInt("min_should", plan.MinShould)
+	if plan.Positive != nil {
+		add("positive", FormatQuery(plan.Positive))
+	}
+	if plan.Negative != nil {
+		add("negative", FormatQuery(plan.Negative))
+	}
+	addFloat("negative_boost", plan.NegativeBoost)
+	addFloat("tie_breaker", plan.TieBreaker)
+
+	if plan.Type == QueryTypeFunctionScore {
+		fs := plan.FunctionScore
+		functions := make([]string, 0, len(fs.Functions))
+		for _, f := range fs.Functions {
+			functions = append(functions, formatFunction(f))
+		}
+		add("functions", "["+strings.Join(functions, ", ")+"]")
+		add("score_mode", strings.TrimSuffix(fs.ScoreMode.String(), " score mode"))
+		add("boost_mode", strings.TrimSuffix(fs.BoostMode.String(), " boost mode"))
+	}
+	if plan.Type == QueryTypeMoreLikeThis {
+		mlt := plan.MoreLikeThis
+		addStrings("fields", mlt.Fields)
+		addStrings("ids", mlt.IDs)
+		addStrings("like", mlt.Like)
+		addInt("min_term_freq", mlt.MinTermFreq)
+		addInt("min_doc_freq", mlt.MinDocFreq)
+		addInt("max_query_terms", mlt.MaxQueryTerms)
+		addStrings("stop_words", mlt.StopWords)
+	}
+
+	b.WriteString(strings.Join(opts, ", "))
+	b.WriteByte(')')
+	if plan.BoostVal != nil {
+		b.WriteString("^" + formatFloat(plan.BoostVal.Value()))
+	}
+}
+
+// formatFunction prints the function with the parameters its type uses,
+// such as field_value_factor(field=price, factor=1.2, modifier=log1p,
+// missing=1, weight=1).
+func formatFunction(f ScoreFunction) string {
+	name := "unknown"
+	if int(f.Type) < len(functionTypes) {
+		name = functionTypes[f.Type]
+	}
+
+	var opts []string
+	add := func(key, value string) {
+		opts = append(opts, key+"="+value)
+	}
+	switch f.Type {
+	case FunctionTypeFieldValueFactor:
+		add("field", f.Field)
+		add("factor", formatFloat(f.Factor))
+		add("modifier", strings.TrimSuffix(f.Modifier.String(), " field value modifier"))
+		add("missing", formatFloat(f.Missing))
+	case FunctionTypeDecayExp, FunctionTypeDecayGauss, FunctionTypeDecayLinear:
+		add("field", f.Field)
+		add("origin", formatFloat(f.Origin))
+		add("scale", formatFloat(f.Scale))
+		add("offset", formatFloat(f.Offset))
+		add("decay", formatFloat(f.Decay))
+		if f.Date {
+			add("date", "true")
+		}
+	case FunctionTypeRandomScore:
+		add("seed", strconv.FormatInt(f.Seed, 10))
+	}
+	add("weight", formatFloat(f.Weight))
+
+	return strings.Replace(name, " ", "_", -1) + "(" + strings.Join(opts, ", ") + ")"
+}
+
+func boundKey(key string, inclusive bool) string {
+	if inclusive {
+		return key + "_inclusive"
+	}
+	return key
+}
+
+func boundSet(bound Bound) bool {
+	switch b := bound.(type) {
+	case string:
+		return b != ""
+	case NullFloat64:
+		return b.Valid
+	case time.Time:
+		return !b.IsZero()
+	default:
+		return b != nil
+	}
+}
+
+func formatBound(bound Bound) string {
+	switch b := bound.(type) {
+	case string:
+		return strconv.Quote(b)
+	case NullFloat64:
+		return formatFloat(b.Float64)
+	case time.Time:
+		return b.Format(time.RFC3339Nano)
+	default:
+		return "unknown"
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
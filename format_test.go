@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    Query
+		expected string
+	}{
+		{
+			name:     "nil",
+			expected: "nil",
+		},
+		{
+			name:     "match",
+			query:    NewQueryMatch("phone").SetField("text").SetFuzziness(1),
+			expected: `match(field=text, matches=["phone"], fuzziness=1)`,
+		},
+		{
+			name: "boolean",
+			query: NewQueryBoolean().
+				AddMust(NewQueryMatch("phone").SetField("text")).
+				AddFilter(NewQueryNumericRange().SetField("price").SetMax(500)).
+				SetBoost(2),
+			expected: `boolean(must=[match(field=text, matches=["phone"])], filter=[numeric_range(field=price, max=500)])^2`,
+		},
+		{
+			name: "function score",
+			query: NewQueryFunctionScore(NewQueryMatchAll(),
+				NewFunctionWeight(2),
+				NewFunctionFieldValueFactor("popularity").SetFactor(1.2).SetModifier(ModifierLog1p).SetMissing(0.5),
+				NewFunctionDecay(FunctionTypeDecayGauss, "price", 100, 50).SetOffset(10).SetDecay(0.3),
+				NewFunctionRandomScore(42).SetWeight(0.5),
+			).SetScoreMode(ScoreModeSum).SetBoostMode(BoostModeReplace),
+			expected: "function_score(must=[match_all()], " +
+				"functions=[weight(weight=2), " +
+				"field_value_factor(field=popularity, factor=1.2, modifier=log1p, missing=0.5, weight=1), " +
+				"gauss_decay(field=price, origin=100, scale=50, offset=10, decay=0.3, weight=1), " +
+				"random_score(seed=42, weight=0.5)], " +
+				"score_mode=sum, boost_mode=replace)",
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatQuery(tt.query))
+		}
+		t.Run(tt.name, fn)
+	}
+}
@@ -0,0 +1,473 @@
+package search
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// QueryFunctionScore modifies the scores of the documents matching the
+// query with score functions. Engines apply the functions as a rescoring
+// layer over the matches of the wrapped query using FunctionScore.Rescore.
+// A nil query matches all documents.
+type QueryFunctionScore struct {
+	Query Query
+	FunctionScore
+	BoostVal *Boost
+}
+
+func NewQueryFunctionScore(q Query, functions ...ScoreFunction) *QueryFunctionScore {
+	return &QueryFunctionScore{
+		Query: q,
+		FunctionScore: FunctionScore{
+			Functions: functions,
+		},
+	}
+}
+
+func (q *QueryFunctionScore) QueryPlan() QueryPlan {
+	inner := q.Query
+	if inner == nil {
+		inner = NewQueryMatchAll()
+	}
+	return QueryPlan{
+		Type:          QueryTypeFunctionScore,
+		Must:          []Query{inner},
+		FunctionScore: q.FunctionScore,
+		BoostVal:      q.BoostVal,
+	}
+}
+
+func (q *QueryFunctionScore) AddFunctions(functions ...ScoreFunction) *QueryFunctionScore {
+	q.Functions = append(q.Functions, functions...)
+	return q
+}
+
+func (q *QueryFunctionScore) SetBoost(b float64) *QueryFunctionScore {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryFunctionScore) SetBoostMode(mode BoostMode) *QueryFunctionScore {
+	q.BoostMode = mode
+	return q
+}
+
+func (q *QueryFunctionScore) SetScoreMode(mode ScoreMode) *QueryFunctionScore {
+	q.ScoreMode = mode
+	return q
+}
+
+// FieldValuer returns the numeric value of a field of the document being
+// rescored. Date values are returned as nanoseconds since the unix epoch.
+type FieldValuer func(field string, date bool) (float64, bool)
+
+// FunctionScore holds the score functions and how their results are
+// combined with each other and with the score of the query.
+type FunctionScore struct {
+	Functions []ScoreFunction
+	ScoreMode ScoreMode
+	BoostMode BoostMode
+}
+
+// Validate verifies the parameters of the functions.
+func (f FunctionScore) Validate() error {
+	for _, fn := range f.Functions {
+		if err := fn.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rescore computes the new score of the document with the score of the
+// query. It fails when a function does not compute a finite score for the
+// document.
+func (f FunctionScore) Rescore(docID string, score float64, values FieldValuer) (float64, error) {
+	if len(f.Functions) == 0 {
+		return score, nil
+	}
+
+	results := make([]float64, 0, len(f.Functions))
+	for _, fn := range f.Functions {
+		result, err := fn.Score(docID, values)
+		if err != nil {
+			return 0, err
+		}
+		results = append(results, result)
+	}
+	return f.BoostMode.combine(score, f.ScoreMode.combine(results)), nil
+}
+
+type ScoreMode int
+
+func (s ScoreMode) String() string {
+	if int(s) >= len(scoreModes) {
+		return "unknown score mode"
+	}
+	return scoreModes[s] + " score mode"
+}
+
+const (
+	ScoreModeMultiply ScoreMode = iota
+	ScoreModeSum
+	ScoreModeAvg
+	ScoreModeFirst
+	ScoreModeMax
+	ScoreModeMin
+)
+
+var scoreModes = [...]string{
+	ScoreModeMultiply: "multiply",
+	ScoreModeSum:      "sum",
+	ScoreModeAvg:      "avg",
+	ScoreModeFirst:    "first",
+	ScoreModeMax:      "max",
+	ScoreModeMin:      "min",
+}
+
+func (s ScoreMode) combine(results []float64) float64 {
+	rv := results[0]
+	switch s {
+	case ScoreModeFirst:
+	case ScoreModeSum, ScoreModeAvg:
+		for _, r := range results[1:] {
+			rv += r
+		}
+		if s == ScoreModeAvg {
+			rv /= float64(len(results))
+		}
+	case ScoreModeMax:
+		for _, r := range results[1:] {
+			rv = math.Max(rv, r)
+		}
+	case ScoreModeMin:
+		for _, r := range results[1:] {
+			rv = math.Min(rv, r)
+		}
+	default:
+		for _, r := range results[1:] {
+			rv *= r
+		}
+	}
+	return rv
+}
+
+type BoostMode int
+
+func (b BoostMode) String() string {
+	if int(b) >= len(boostModes) {
+		return "unknown boost mode"
+	}
+	return boostModes[b] + " boost mode"
+}
+
+const (
+	BoostModeMultiply BoostMode = iota
+	BoostModeReplace
+	BoostModeSum
+	BoostModeAvg
+	BoostModeMax
+	BoostModeMin
+)
+
+var boostModes = [...]string{
+	BoostModeMultiply: "multiply",
+	BoostModeReplace:  "replace",
+	BoostModeSum:      "sum",
+	BoostModeAvg:      "avg",
+	BoostModeMax:      "max",
+	BoostModeMin:      "min",
+}
+
+func (b BoostMode) combine(score, fnScore float64) float64 {
+	switch b {
+	case BoostModeReplace:
+		return fnScore
+	case BoostModeSum:
+		return score + fnScore
+	case BoostModeAvg:
+		return (score + fnScore) / 2
+	case BoostModeMax:
+		return math.Max(score, fnScore)
+	case BoostModeMin:
+		return math.Min(score, fnScore)
+	default:
+		return score * fnScore
+	}
+}
+
+type FunctionType int
+
+func (f FunctionType) String() string {
+	if int(f) >= len(functionTypes) {
+		return "unknown function type"
+	}
+	return functionTypes[f] + " function type"
+}
+
+const (
+	FunctionTypeWeight FunctionType = iota
+	FunctionTypeFieldValueFactor
+	FunctionTypeDecayExp
+	FunctionTypeDecayGauss
+	FunctionTypeDecayLinear
+	FunctionTypeRandomScore
+)
+
+var functionTypes = [...]string{
+	FunctionTypeWeight:           "weight",
+	FunctionTypeFieldValueFactor: "field value factor",
+	FunctionTypeDecayExp:         "exp decay",
+	FunctionTypeDecayGauss:       "gauss decay",
+	FunctionTypeDecayLinear:      "linear decay",
+	FunctionTypeRandomScore:      "random score",
+}
+
+type FieldValueModifier int
+
+func (m FieldValueModifier) String() string {
+	if int(m) >= len(fieldValueModifiers) {
+		return "unknown field value modifier"
+	}
+	return fieldValueModifiers[m] + " field value modifier"
+}
+
+const (
+	ModifierNone FieldValueModifier = iota
+	ModifierLog
+	ModifierLog1p
+	ModifierLog2p
+	ModifierLn
+	ModifierLn1p
+	ModifierLn2p
+	ModifierSquare
+	ModifierSqrt
+	ModifierReciprocal
+)
+
+var fieldValueModifiers = [...]string{
+	ModifierNone:       "none",
+	ModifierLog:        "log",
+	ModifierLog1p:      "log1p",
+	ModifierLog2p:      "log2p",
+	ModifierLn:         "ln",
+	ModifierLn1p:       "ln1p",
+	ModifierLn2p:       "ln2p",
+	ModifierSquare:     "square",
+	ModifierSqrt:       "sqrt",
+	ModifierReciprocal: "reciprocal",
+}
+
+func (m FieldValueModifier) apply(v float64) float64 {
+	switch m {
+	case ModifierLog:
+		return math.Log10(v)
+	case ModifierLog1p:
+		return math.Log10(v + 1)
+	case ModifierLog2p:
+		return math.Log10(v + 2)
+	case ModifierLn:
+		return math.Log(v)
+	case ModifierLn1p:
+		return math.Log1p(v)
+	case ModifierLn2p:
+		return math.Log(v + 2)
+	case ModifierSquare:
+		return v * v
+	case ModifierSqrt:
+		return math.Sqrt(v)
+	case ModifierReciprocal:
+		return 1 / v
+	default:
+		return v
+	}
+}
+
+// ScoreFunction computes a score for a document. The result of every
+// function is multiplied by its weight.
+type ScoreFunction struct {
+	Type   FunctionType
+	Weight float64
+	Field  string
+
+	// Factor, Modifier and Missing are used by the field value factor
+	// function, which computes modifier(factor * value). Documents without
+	// the field use the Missing value.
+	Factor   float64
+	Modifier FieldValueModifier
+	Missing  float64
+
+	// Origin, Scale, Offset and Decay are used by the decay functions.
+	// Documents at Offset from the Origin get the full score, documents at
+	// Offset+Scale from the Origin a score of Decay. Dates are expressed in
+	// nanoseconds.
+	Origin float64
+	Scale  float64
+	Offset float64
+	Decay  float64
+	Date   bool
+
+	Seed int64
+}
+
+func NewFunctionWeight(weight float64) ScoreFunction {
+	return ScoreFunction{
+		Type:   FunctionTypeWeight,
+		Weight: weight,
+	}
+}
+
+func NewFunctionFieldValueFactor(field string) ScoreFunction {
+	return ScoreFunction{
+		Type:    FunctionTypeFieldValueFactor,
+		Weight:  1,
+		Field:   field,
+		Factor:  1,
+		Missing: 1,
+	}
+}
+
+// NewFunctionDecay creates a decay function of the exp, gauss or linear
+// decay function type over a numeric field.
+func NewFunctionDecay(typ FunctionType, field string, origin, scale float64) ScoreFunction {
+	return ScoreFunction{
+		Type:   typ,
+		Weight: 1,
+		Field:  field,
+		Origin: origin,
+		Scale:  scale,
+		Decay:  0.5,
+	}
+}
+
+// NewFunctionDateDecay creates a decay function of the exp, gauss or linear
+// decay function type over a date field.
+func NewFunctionDateDecay(typ FunctionType, field string, origin time.Time, scale time.Duration) ScoreFunction {
+	f := NewFunctionDecay(typ, field, float64(origin.UnixNano()), float64(scale))
+	f.Date = true
+	return f
+}
+
+// NewFunctionRandomScore creates a function scoring documents uniformly
+// between 0 and 1. The score of a document is stable for the same seed.
+func NewFunctionRandomScore(seed int64) ScoreFunction {
+	return ScoreFunction{
+		Type:   FunctionTypeRandomScore,
+		Weight: 1,
+		Seed:   seed,
+	}
+}
+
+func (f ScoreFunction) SetDecay(decay float64) ScoreFunction {
+	f.Decay = decay
+	return f
+}
+
+func (f ScoreFunction) SetFactor(factor float64) ScoreFunction {
+	f.Factor = factor
+	return f
+}
+
+func (f ScoreFunction) SetMissing(missing float64) ScoreFunction {
+	f.Missing = missing
+	return f
+}
+
+func (f ScoreFunction) SetModifier(modifier FieldValueModifier) ScoreFunction {
+	f.Modifier = modifier
+	return f
+}
+
+// SetOffset sets the distance from the origin within which documents get
+// the full score. Date offsets are expressed in nanoseconds.
+func (f ScoreFunction) SetOffset(offset float64) ScoreFunction {
+	f.Offset = offset
+	return f
+}
+
+func (f ScoreFunction) SetWeight(weight float64) ScoreFunction {
+	f.Weight = weight
+	return f
+}
+
+// Validate verifies the parameters of the decay functions, which require a
+// positive scale and a decay between 0 and 1.
+func (f ScoreFunction) Validate() error {
+	switch f.Type {
+	case FunctionTypeDecayExp, FunctionTypeDecayGauss, FunctionTypeDecayLinear:
+		if f.Scale <= 0 {
+			return fmt.Errorf("%s requires a positive scale", f.Type)
+		}
+		if f.Decay <= 0 || f.Decay >= 1 {
+			return fmt.Errorf("%s requires a decay between 0 and 1, got %v", f.Type, f.Decay)
+		}
+	}
+	return nil
+}
+
+// Score computes the weighted result of the function for the document. It
+// fails for decay functions with invalid parameters and when the result is
+// not a finite number, such as the log of a field value of 0 or below.
+func (f ScoreFunction) Score(docID string, values FieldValuer) (float64, error) {
+	var score float64
+	switch f.Type {
+	case FunctionTypeFieldValueFactor:
+		v, ok := values(f.Field, false)
+		if !ok {
+			v = f.Missing
+		}
+		score = f.Modifier.apply(f.Factor * v)
+		if math.IsNaN(score) || math.IsInf(score, 0) {
+			return 0, fmt.Errorf("%s of %v for field %q of document %q is not a finite number", f.Modifier, f.Factor*v, f.Field, docID)
+		}
+	case FunctionTypeDecayExp, FunctionTypeDecayGauss, FunctionTypeDecayLinear:
+		// the decay divides by the scale and the log of the decay
+		if err := f.Validate(); err != nil {
+			return 0, err
+		}
+		v, ok := values(f.Field, f.Date)
+		if !ok {
+			score = 1
+			break
+		}
+		score = f.decay(v)
+	case FunctionTypeRandomScore:
+		score = randomScore(f.Seed, docID)
+	default:
+		score = 1
+	}
+
+	score *= f.Weight
+	if math.IsNaN(score) || math.IsInf(score, 0) {
+		return 0, fmt.Errorf("%s for field %q of document %q is not a finite number", f.Type, f.Field, docID)
+	}
+	return score, nil
+}
+
+func (f ScoreFunction) decay(v float64) float64 {
+	distance := math.Max(0, math.Abs(v-f.Origin)-f.Offset)
+	switch f.Type {
+	case FunctionTypeDecayExp:
+		lambda := math.Log(f.Decay) / f.Scale
+		return math.Exp(lambda * distance)
+	case FunctionTypeDecayGauss:
+		sigmaSquared := -f.Scale * f.Scale / (2 * math.Log(f.Decay))
+		return math.Exp(-distance * distance / (2 * sigmaSquared))
+	default:
+		s := f.Scale / (1 - f.Decay)
+		return math.Max(0, (s-distance)/s)
+	}
+}
+
+func randomScore(seed int64, docID string) float64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(seed))
+	h.Write(b[:])
+	h.Write([]byte(docID))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
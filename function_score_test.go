@@ -0,0 +1,106 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreFunction_Score(t *testing.T) {
+	value := func(v float64) FieldValuer {
+		return func(string, bool) (float64, bool) {
+			return v, true
+		}
+	}
+
+	tests := []struct {
+		name     string
+		fn       ScoreFunction
+		value    float64
+		expected float64
+		wantErr  bool
+	}{
+		{
+			name:     "log",
+			fn:       NewFunctionFieldValueFactor("v").SetModifier(ModifierLog),
+			value:    100,
+			expected: 2,
+		},
+		{
+			name:    "log of zero",
+			fn:      NewFunctionFieldValueFactor("v").SetModifier(ModifierLog),
+			value:   0,
+			wantErr: true,
+		},
+		{
+			name:    "log of a negative value",
+			fn:      NewFunctionFieldValueFactor("v").SetModifier(ModifierLog),
+			value:   -5,
+			wantErr: true,
+		},
+		{
+			name:    "ln of zero",
+			fn:      NewFunctionFieldValueFactor("v").SetModifier(ModifierLn),
+			value:   0,
+			wantErr: true,
+		},
+		{
+			name:    "sqrt of a negative value",
+			fn:      NewFunctionFieldValueFactor("v").SetModifier(ModifierSqrt),
+			value:   -1,
+			wantErr: true,
+		},
+		{
+			name:    "reciprocal of zero",
+			fn:      NewFunctionFieldValueFactor("v").SetModifier(ModifierReciprocal),
+			value:   0,
+			wantErr: true,
+		},
+		{
+			name:     "log1p of zero",
+			fn:       NewFunctionFieldValueFactor("v").SetModifier(ModifierLog1p),
+			value:    0,
+			expected: 0,
+		},
+		{
+			name:     "square of a negative value",
+			fn:       NewFunctionFieldValueFactor("v").SetModifier(ModifierSquare),
+			value:    -3,
+			expected: 9,
+		},
+		{
+			name:    "decay without scale",
+			fn:      NewFunctionDecay(FunctionTypeDecayExp, "v", 0, 0),
+			value:   1,
+			wantErr: true,
+		},
+		{
+			name:    "gauss decay of 1",
+			fn:      NewFunctionDecay(FunctionTypeDecayGauss, "v", 0, 10).SetDecay(1),
+			value:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			score, err := tt.fn.Score("doc", value(tt.value))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, score, 1e-9)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestScoreFunction_Validate(t *testing.T) {
+	assert.NoError(t, NewFunctionDecay(FunctionTypeDecayLinear, "v", 0, 10).Validate())
+	assert.Error(t, NewFunctionDecay(FunctionTypeDecayLinear, "v", 0, 0).Validate())
+	assert.Error(t, NewFunctionDecay(FunctionTypeDecayLinear, "v", 0, 10).SetDecay(0).Validate())
+	assert.Error(t, NewFunctionDecay(FunctionTypeDecayLinear, "v", 0, 10).SetDecay(1).Validate())
+	assert.NoError(t, NewFunctionFieldValueFactor("v").SetModifier(ModifierLog).Validate())
+}
@@ -0,0 +1,231 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// languageStopWords holds the most common words of each language, which is
+// enough to tell apart reasonably sized samples of text.
+var languageStopWords = map[Language]map[string]bool{
+	LanguageEnglish: wordSet(
+		"a", "and", "are", "for", "from", "has", "have", "in", "is", "it", "not", "of",
+		"on", "that", "the", "this", "to", "was", "were", "which", "with", "you",
+	),
+	LanguageFrench: wordSet(
+		"au", "aux", "avec", "ce", "dans", "de", "des", "du", "elle", "est", "et", "il",
+		"la", "le", "les", "mais", "ne", "nous", "ou", "par", "pas", "pour", "que",
+		"qui", "sont", "sur", "un", "une", "vous",
+	),
+	LanguageGerman: wordSet(
+		"auf", "aus", "das", "dem", "den", "der", "die", "ein", "eine", "einen", "es",
+		"für", "ich", "ist", "mit", "nicht", "sich", "sie", "sind", "und", "von", "wir",
+		"zu", "zum", "zur",
+	),
+}
+
+// languageRunes are letters that hint at a language when found in text.
+var languageRunes = map[Language]string{
+	LanguageFrench: "àâçéèêëîïôûùœ",
+	LanguageGerman: "äöüß",
+}
+
+// DetectLanguage guesses the language of the text from the candidates. When
+// no candidates are provided all supported languages are considered. The
+// first candidate is returned when the text gives no indication.
+func DetectLanguage(text string, candidates ...Language) Language {
+	if len(candidates) == 0 {
+		candidates = []Language{LanguageEnglish, LanguageFrench, LanguageGerman, LanguageJapanese}
+	}
+
+	scores := make(map[Language]int)
+	var letters, kana, han int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.IsLetter(r):
+			letters++
+			for lang, runes := range languageRunes {
+				if strings.ContainsRune(runes, unicode.ToLower(r)) {
+					scores[lang]++
+				}
+			}
+		}
+	}
+	if kana+han > letters {
+		scores[LanguageJapanese] += kana + han
+	}
+
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), isNotLetter) {
+		for lang, words := range languageStopWords {
+			if words[w] {
+				scores[lang] += 2
+			}
+		}
+	}
+
+	best := candidates[0]
+	for _, lang := range candidates[1:] {
+		if scores[lang] > scores[best] {
+			best = lang
+		}
+	}
+	return best
+}
+
+// LanguageField returns the name of the language variant of the field.
+func LanguageField(field string, lang Language) string {
+	return field + "_" + string(lang)
+}
+
+// NewQueryMatchLanguages matches against every language variant of the field.
+func NewQueryMatchLanguages(match, field string, langs ...Language) *QueryBoolean {
+	q := NewQueryBoolean()
+	for _, lang := range langs {
+		q.AddShould(NewQueryMatch(match).SetField(LanguageField(field, lang)))
+	}
+	return q
+}
+
+// RouteLanguages moves the text of every field mapped with languages into
+// the language variant of the field matching the detected language. Maps
+// and structs are supported, struct fields being named by their json tag.
+// The data is left untouched: the maps holding routed fields are copied and
+// every other value is kept as is. Data is returned as is when the schema
+// has no such fields or it is neither a map nor a struct. Fields mapped with
+// languages holding a value other than text fail the routing.
+func (s Schema) RouteLanguages(data interface{}) (interface{}, error) {
+	var fields []FieldMapping
+	for _, f := range s.Fields {
+		if len(f.Languages) > 0 {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	doc, ok := toDocMap(data)
+	if !ok {
+		return data, nil
+	}
+
+	for _, f := range fields {
+		parts := strings.Split(f.Path, ".")
+		parent := doc
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := toDocMap(parent[p])
+			if !ok {
+				parent = nil
+				break
+			}
+			parent[p] = child
+			parent = child
+		}
+		if parent == nil {
+			continue
+		}
+
+		last := parts[len(parts)-1]
+		value, ok := parent[last]
+		if !ok || value == nil {
+			continue
+		}
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.String {
+			return nil, fmt.Errorf("field %q mapped with languages holds a %T, not text", f.Path, value)
+		}
+		text := v.String()
+		delete(parent, last)
+		parent[LanguageField(last, DetectLanguage(text, f.Languages...))] = text
+	}
+	return doc, nil
+}
+
+// toDocMap returns a copy of the fields of a map with string keys or of a
+// struct, following pointers.
+func toDocMap(data interface{}) (map[string]interface{}, bool) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		doc := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			doc[iter.Key().String()] = iter.Value().Interface()
+		}
+		return doc, true
+	case v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}):
+		doc := make(map[string]interface{}, v.NumField())
+		addStructFields(doc, v)
+		return doc, true
+	default:
+		return nil, false
+	}
+}
+
+// addStructFields adds the exported fields of the struct under their json
+// names, inlining the fields of untagged embedded structs like
+// encoding/json.
+func addStructFields(doc map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx >= 0 {
+				tag, opts = tag[:idx], tag[idx:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := v.Field(i)
+		if f.Anonymous && name == f.Name {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				addStructFields(doc, fv)
+				continue
+			}
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if _, exists := doc[name]; !exists {
+			doc[name] = fv.Interface()
+		}
+	}
+}
+
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
+func wordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		candidates []Language
+		expected   Language
+	}{
+		{
+			name:     "english",
+			text:     "The dogs were running through the park with their owners",
+			expected: LanguageEnglish,
+		},
+		{
+			name:     "french",
+			text:     "Les chiens courent dans le parc avec leurs maîtres",
+			expected: LanguageFrench,
+		},
+		{
+			name:     "german",
+			text:     "Die Hunde sind mit ihren Besitzern durch den Park gelaufen",
+			expected: LanguageGerman,
+		},
+		{
+			name:     "german letters",
+			text:     "Grüße",
+			expected: LanguageGerman,
+		},
+		{
+			name:     "japanese",
+			text:     "犬たちは飼い主と一緒に東京の公園を走っています",
+			expected: LanguageJapanese,
+		},
+		{
+			name:       "restricted to candidates",
+			text:       "Les chiens courent dans le parc avec leurs maîtres",
+			candidates: []Language{LanguageEnglish, LanguageGerman},
+			expected:   LanguageEnglish,
+		},
+		{
+			name:       "no indication returns first candidate",
+			text:       "1234",
+			candidates: []Language{LanguageGerman, LanguageEnglish},
+			expected:   LanguageGerman,
+		},
+		{
+			name:     "empty text",
+			text:     "",
+			expected: LanguageEnglish,
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectLanguage(tt.text, tt.candidates...))
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestSchemaRouteLanguages(t *testing.T) {
+	schema := Schema{
+		Fields: []FieldMapping{
+			*NewFieldMapping("body", FieldTypeText).SetLanguages(LanguageEnglish, LanguageGerman),
+			*NewFieldMapping("nest.body", FieldTypeText).SetLanguages(LanguageEnglish, LanguageGerman),
+		},
+	}
+	published := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("map", func(t *testing.T) {
+		nest := map[string]interface{}{"body": "Der Hund ist im Park"}
+		data := map[string]interface{}{
+			"body":      "The dog is in the park",
+			"published": published,
+			"views":     42,
+			"nest":      nest,
+		}
+
+		routed, err := schema.RouteLanguages(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"body_en":   "The dog is in the park",
+			"published": published,
+			"views":     42,
+			"nest":      map[string]interface{}{"body_de": "Der Hund ist im Park"},
+		}, routed)
+
+		assert.Equal(t, "The dog is in the park", data["body"], "data must be left untouched")
+		assert.Equal(t, map[string]interface{}{"body": "Der Hund ist im Park"}, nest)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		type Meta struct {
+			Views int `json:"views"`
+		}
+		type doc struct {
+			Meta
+			Body      string    `json:"body"`
+			Published time.Time `json:"published"`
+			Draft     bool      `json:"draft,omitempty"`
+			Internal  string    `json:"-"`
+			hidden    string
+		}
+
+		routed, err := schema.RouteLanguages(&doc{
+			Meta:      Meta{Views: 42},
+			Body:      "The dog is in the park",
+			Published: published,
+			Internal:  "internal",
+			hidden:    "hidden",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"body_en":   "The dog is in the park",
+			"published": published,
+			"views":     42,
+		}, routed)
+	})
+
+	t.Run("no routed fields", func(t *testing.T) {
+		data := struct{ Views int }{Views: 42}
+
+		routed, err := Schema{}.RouteLanguages(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, routed)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		data := map[string]interface{}{"body": nil, "views": 42}
+
+		routed, err := schema.RouteLanguages(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, routed)
+	})
+
+	t.Run("non text field", func(t *testing.T) {
+		_, err := schema.RouteLanguages(map[string]interface{}{
+			"nest": map[string]interface{}{"body": 42},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nest.body")
+	})
+
+	t.Run("not a document", func(t *testing.T) {
+		routed, err := schema.RouteLanguages("text")
+		require.NoError(t, err)
+		assert.Equal(t, "text", routed)
+	})
+}
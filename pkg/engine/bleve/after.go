@@ -0,0 +1,127 @@
+package bleve
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/numeric"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+// searchAfterQuery emulates search after, which bleve does not support,
+// by filtering out the matches of the query that do not sort after the
+// after document. The sort values of the matches are computed as the
+// collector computes them.
+type searchAfterQuery struct {
+	Query query.Query
+	Sort  ogsearch.SortOrder
+	After *ogsearch.DocumentMatch
+}
+
+func newSearchAfterQuery(q query.Query, order ogsearch.SortOrder, sorts []search.Sort, values []string) (*searchAfterQuery, error) {
+	if len(values) != len(sorts) {
+		return nil, fmt.Errorf("search after requires %d sort values, got %d", len(sorts), len(values))
+	}
+
+	after := &ogsearch.DocumentMatch{Sort: make([]string, len(values))}
+	for i, s := range sorts {
+		v := values[i]
+		switch s.Type {
+		case search.SortTypeScore:
+			score, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid score search after value %q: %v", v, err)
+			}
+			after.Score = score
+		case search.SortTypeGeoDistance:
+			dist, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid geo distance search after value %q: %v", v, err)
+			}
+			v = string(numeric.MustNewPrefixCodedInt64(numeric.Float64ToInt64(dist), 0))
+		}
+		after.Sort[i] = v
+	}
+
+	return &searchAfterQuery{
+		Query: q,
+		Sort:  order.Copy(),
+		After: after,
+	}, nil
+}
+
+func (q *searchAfterQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	s, err := q.Query.Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	return &searchAfterSearcher{
+		Searcher:      s,
+		reader:        i,
+		sort:          q.Sort.Copy(),
+		fields:        q.Sort.RequiredFields(),
+		cachedScoring: q.Sort.CacheIsScore(),
+		cachedDesc:    q.Sort.CacheDescending(),
+		after:         q.After,
+	}, nil
+}
+
+type searchAfterSearcher struct {
+	ogsearch.Searcher
+	reader        index.IndexReader
+	sort          ogsearch.SortOrder
+	fields        []string
+	cachedScoring []bool
+	cachedDesc    []bool
+	after         *ogsearch.DocumentMatch
+}
+
+func (s *searchAfterSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Next(ctx)
+	return s.skip(ctx, dm, err)
+}
+
+func (s *searchAfterSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Advance(ctx, ID)
+	return s.skip(ctx, dm, err)
+}
+
+// skip returns the first match from dm on that sorts after the after
+// document.
+func (s *searchAfterSearcher) skip(ctx *ogsearch.SearchContext, dm *ogsearch.DocumentMatch, err error) (*ogsearch.DocumentMatch, error) {
+	for err == nil && dm != nil {
+		var after bool
+		after, err = s.isAfter(dm)
+		if err != nil || after {
+			break
+		}
+		ctx.DocumentMatchPool.Put(dm)
+		dm, err = s.Searcher.Next(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dm, nil
+}
+
+func (s *searchAfterSearcher) isAfter(dm *ogsearch.DocumentMatch) (bool, error) {
+	if len(s.fields) > 0 {
+		err := s.reader.DocumentVisitFieldTerms(dm.IndexInternalID, s.fields, s.sort.UpdateVisitor)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	id, err := s.reader.ExternalID(dm.IndexInternalID)
+	if err != nil {
+		return false, err
+	}
+
+	doc := &ogsearch.DocumentMatch{ID: id, Score: dm.Score}
+	s.sort.Value(doc)
+	return s.sort.Compare(s.cachedScoring, s.cachedDesc, doc, s.after) > 0, nil
+}
@@ -0,0 +1,392 @@
+package bleve
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	_ "github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	_ "github.com/blevesearch/bleve/analysis/analyzer/simple"
+	"github.com/blevesearch/bleve/analysis/char/html"
+	charregexp "github.com/blevesearch/bleve/analysis/char/regexp"
+	"github.com/blevesearch/bleve/analysis/char/zerowidthnonjoiner"
+	"github.com/blevesearch/bleve/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/analysis/lang/de"
+	"github.com/blevesearch/bleve/analysis/lang/en"
+	"github.com/blevesearch/bleve/analysis/lang/fr"
+	"github.com/blevesearch/bleve/analysis/token/edgengram"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/token/ngram"
+	"github.com/blevesearch/bleve/analysis/token/porter"
+	"github.com/blevesearch/bleve/analysis/token/stop"
+	"github.com/blevesearch/bleve/analysis/tokenizer/letter"
+	tokenregexp "github.com/blevesearch/bleve/analysis/tokenizer/regexp"
+	"github.com/blevesearch/bleve/analysis/tokenizer/single"
+	unicodetokenizer "github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/analysis/tokenizer/whitespace"
+	"github.com/blevesearch/bleve/analysis/tokenmap"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/registry"
+	"github.com/jsteenb2/search"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	asciiFoldingName = "search_ascii_folding"
+	synonymName      = "search_synonym"
+)
+
+func init() {
+	registry.RegisterTokenFilter(asciiFoldingName, func(map[string]interface{}, *registry.Cache) (analysis.TokenFilter, error) {
+		return new(asciiFoldingFilter), nil
+	})
+	registry.RegisterTokenFilter(synonymName, newSynonymFilter)
+}
+
+func applySchema(im *mapping.IndexMappingImpl, schema search.Schema) error {
+	if err := schema.Validate(); err != nil {
+		return err
+	}
+
+	for _, a := range schema.Analyzers {
+		if err := addAnalyzer(im, a); err != nil {
+			return fmt.Errorf("failed to add analyzer %q: %w", a.Name, err)
+		}
+	}
+
+	var completionAdded bool
+	for _, f := range schema.Fields {
+		if f.Type == search.FieldTypeCompletion {
+			if !completionAdded {
+				if err := addCompletionAnalyzers(im); err != nil {
+					return err
+				}
+				completionAdded = true
+			}
+			addFieldMappingAt(im.DefaultMapping, f.Path, newCompletionFieldMappings(f.Path)...)
+			continue
+		}
+		if len(f.Languages) > 0 {
+			for _, lang := range f.Languages {
+				analyzer, err := languageAnalyzer(lang)
+				if err != nil {
+					return fmt.Errorf("field %q: %w", f.Path, err)
+				}
+				fm := mapping.NewTextFieldMapping()
+				fm.Analyzer = analyzer
+				addFieldMappingAt(im.DefaultMapping, search.LanguageField(f.Path, lang), fm)
+			}
+			continue
+		}
+
+		fm, err := newFieldMapping(f)
+		if err != nil {
+			return err
+		}
+		addFieldMappingAt(im.DefaultMapping, f.Path, fm)
+	}
+	return nil
+}
+
+func addAnalyzer(im *mapping.IndexMappingImpl, a search.Analyzer) error {
+	charFilters := make([]interface{}, 0, len(a.CharFilters))
+	for i, cf := range a.CharFilters {
+		name := fmt.Sprintf("%s_char_filter_%d", a.Name, i)
+		switch cf.Type {
+		case search.CharFilterTypeHTML:
+			name = html.Name
+		case search.CharFilterTypeRegexp:
+			err := im.AddCustomCharFilter(name, map[string]interface{}{
+				"type":    charregexp.Name,
+				"regexp":  cf.Regexp,
+				"replace": cf.Replace,
+			})
+			if err != nil {
+				return err
+			}
+		case search.CharFilterTypeZeroWidth:
+			name = zerowidthnonjoiner.Name
+		default:
+			return fmt.Errorf("unexpected char filter: %s", cf.Type)
+		}
+		charFilters = append(charFilters, name)
+	}
+
+	var tokenizer string
+	switch a.Tokenizer.Type {
+	case search.TokenizerTypeKeyword:
+		tokenizer = single.Name
+	case search.TokenizerTypeLetter:
+		tokenizer = letter.Name
+	case search.TokenizerTypeRegexp:
+		tokenizer = a.Name + "_tokenizer"
+		err := im.AddCustomTokenizer(tokenizer, map[string]interface{}{
+			"type":   tokenregexp.Name,
+			"regexp": a.Tokenizer.Regexp,
+		})
+		if err != nil {
+			return err
+		}
+	case search.TokenizerTypeUnicode:
+		tokenizer = unicodetokenizer.Name
+	case search.TokenizerTypeWhitespace:
+		tokenizer = whitespace.Name
+	default:
+		return fmt.Errorf("unexpected tokenizer: %s", a.Tokenizer.Type)
+	}
+
+	tokenFilters := make([]interface{}, 0, len(a.TokenFilters))
+	for i, tf := range a.TokenFilters {
+		name, err := addTokenFilter(im, fmt.Sprintf("%s_token_filter_%d", a.Name, i), tf)
+		if err != nil {
+			return err
+		}
+		tokenFilters = append(tokenFilters, name)
+	}
+
+	return im.AddCustomAnalyzer(a.Name, map[string]interface{}{
+		"type":          custom.Name,
+		"char_filters":  charFilters,
+		"tokenizer":     tokenizer,
+		"token_filters": tokenFilters,
+	})
+}
+
+func addTokenFilter(im *mapping.IndexMappingImpl, name string, tf search.TokenFilter) (string, error) {
+	switch tf.Type {
+	case search.TokenFilterTypeASCIIFolding:
+		return asciiFoldingName, nil
+	case search.TokenFilterTypeEdgeNgram:
+		return name, im.AddCustomTokenFilter(name, map[string]interface{}{
+			"type": edgengram.Name,
+			"back": tf.Back,
+			"min":  float64(tf.Min),
+			"max":  float64(tf.Max),
+		})
+	case search.TokenFilterTypeLowercase:
+		return lowercase.Name, nil
+	case search.TokenFilterTypeNgram:
+		return name, im.AddCustomTokenFilter(name, map[string]interface{}{
+			"type": ngram.Name,
+			"min":  float64(tf.Min),
+			"max":  float64(tf.Max),
+		})
+	case search.TokenFilterTypeStemmer:
+		switch tf.Language {
+		case search.LanguageEnglish:
+			return porter.Name, nil
+		case search.LanguageFrench:
+			return fr.LightStemmerName, nil
+		case search.LanguageGerman:
+			return de.LightStemmerName, nil
+		default:
+			return "", fmt.Errorf("no stemmer available for language %q", tf.Language)
+		}
+	case search.TokenFilterTypeStop:
+		if tf.Language != "" {
+			return stopFilterName(tf.Language)
+		}
+
+		tokens := make([]interface{}, 0, len(tf.Words))
+		for _, w := range tf.Words {
+			tokens = append(tokens, w)
+		}
+		err := im.AddCustomTokenMap(name+"_words", map[string]interface{}{
+			"type":   tokenmap.Name,
+			"tokens": tokens,
+		})
+		if err != nil {
+			return "", err
+		}
+		return name, im.AddCustomTokenFilter(name, map[string]interface{}{
+			"type":           stop.Name,
+			"stop_token_map": name + "_words",
+		})
+	case search.TokenFilterTypeSynonym:
+		rules := make([]interface{}, 0, len(tf.Synonyms.Rules()))
+		for _, r := range tf.Synonyms.Rules() {
+			rules = append(rules, r.String())
+		}
+		return name, im.AddCustomTokenFilter(name, map[string]interface{}{
+			"type":  synonymName,
+			"rules": rules,
+		})
+	default:
+		return "", fmt.Errorf("unexpected token filter: %s", tf.Type)
+	}
+}
+
+func stopFilterName(lang search.Language) (string, error) {
+	switch lang {
+	case search.LanguageEnglish:
+		return en.StopName, nil
+	case search.LanguageFrench:
+		return fr.StopName, nil
+	case search.LanguageGerman:
+		return de.StopName, nil
+	default:
+		return "", fmt.Errorf("no stop words available for language %q", lang)
+	}
+}
+
+func languageAnalyzer(lang search.Language) (string, error) {
+	switch lang {
+	case search.LanguageEnglish:
+		return en.AnalyzerName, nil
+	case search.LanguageFrench:
+		return fr.AnalyzerName, nil
+	case search.LanguageGerman:
+		return de.AnalyzerName, nil
+	case search.LanguageJapanese:
+		return cjk.AnalyzerName, nil
+	default:
+		return "", fmt.Errorf("no analyzer available for language %q", lang)
+	}
+}
+
+func newFieldMapping(f search.FieldMapping) (*mapping.FieldMapping, error) {
+	var fm *mapping.FieldMapping
+	switch f.Type {
+	case search.FieldTypeText:
+		fm = mapping.NewTextFieldMapping()
+		fm.Analyzer = f.Analyzer
+		if f.Language != "" {
+			analyzer, err := languageAnalyzer(f.Language)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Path, err)
+			}
+			fm.Analyzer = analyzer
+		}
+	case search.FieldTypeBoolean:
+		fm = mapping.NewBooleanFieldMapping()
+	case search.FieldTypeDateTime:
+		fm = mapping.NewDateTimeFieldMapping()
+	case search.FieldTypeGeoPoint:
+		fm = mapping.NewGeoPointFieldMapping()
+	case search.FieldTypeNumeric:
+		fm = mapping.NewNumericFieldMapping()
+	default:
+		return nil, fmt.Errorf("field %q: unexpected %s", f.Path, f.Type)
+	}
+	return fm, nil
+}
+
+// addFieldMappingAt adds the field mapping to the document mapping at the
+// dotted path, creating any intermediate document mappings along the way.
+func addFieldMappingAt(dm *mapping.DocumentMapping, path string, fms ...*mapping.FieldMapping) {
+	parts := strings.Split(path, ".")
+	for _, p := range parts[:len(parts)-1] {
+		sub, ok := dm.Properties[p]
+		if !ok {
+			sub = mapping.NewDocumentMapping()
+			dm.AddSubDocumentMapping(p, sub)
+		}
+		dm = sub
+	}
+	dm.AddFieldMappingsAt(parts[len(parts)-1], fms...)
+}
+
+type asciiFoldingFilter struct{}
+
+func (a *asciiFoldingFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = []byte(foldASCII(string(token.Term)))
+	}
+	return input
+}
+
+var asciiFoldings = map[rune]string{
+	'Æ': "AE", 'æ': "ae",
+	'Đ': "D", 'đ': "d",
+	'Ł': "L", 'ł': "l",
+	'Œ': "OE", 'œ': "oe",
+	'Ø': "O", 'ø': "o",
+	'ß': "ss",
+	'Þ': "TH", 'þ': "th",
+}
+
+func foldASCII(s string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if folded, ok := asciiFoldings[r]; ok {
+			sb.WriteString(folded)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// synonymFilter replaces every token sequence matching a synonym input with
+// the outputs of the synonym. The outputs span the offsets of the tokens they
+// replace, and the positions of the following tokens are shifted by the
+// difference in length of the longest output and the sequence replaced.
+type synonymFilter struct {
+	synonyms *search.Synonyms
+}
+
+func newSynonymFilter(config map[string]interface{}, _ *registry.Cache) (analysis.TokenFilter, error) {
+	rules, ok := config["rules"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must specify synonym rules")
+	}
+
+	synonyms := search.NewSynonyms()
+	for _, r := range rules {
+		rule, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("synonym rule must be a string, got %T", r)
+		}
+		if err := synonyms.AddRule(rule); err != nil {
+			return nil, err
+		}
+	}
+	return &synonymFilter{synonyms: synonyms}, nil
+}
+
+func (s *synonymFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	words := make([]string, 0, len(input))
+	for _, token := range input {
+		words = append(words, string(token.Term))
+	}
+
+	// shift is the number of positions the tokens following the replaced
+	// sequences move by, as replacements may be longer or shorter than the
+	// sequence they replace.
+	var shift int
+	output := make(analysis.TokenStream, 0, len(input))
+	for i := 0; i < len(input); {
+		n, replacements := s.synonyms.Lookup(words[i:])
+		if n == 0 {
+			input[i].Position += shift
+			output = append(output, input[i])
+			i++
+			continue
+		}
+
+		first, last := input[i], input[i+n-1]
+		var longest int
+		for _, replacement := range replacements {
+			for j, w := range replacement {
+				output = append(output, &analysis.Token{
+					Start:    first.Start,
+					End:      last.End,
+					Term:     []byte(w),
+					Position: first.Position + shift + j,
+					Type:     first.Type,
+				})
+			}
+			if len(replacement) > longest {
+				longest = len(replacement)
+			}
+		}
+		shift += longest - (last.Position - first.Position + 1)
+		i += n
+	}
+	return output
+}
@@ -0,0 +1,116 @@
+package bleve
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+// maxCollapseWindow is the maximum number of matches a collapsed search
+// fetches. Collapsing queries with more matches fails, as the groups and
+// their totals can only be computed from every match.
+var maxCollapseWindow = 10000
+
+// searchCollapsed emulates collapsing, which bleve does not support, by
+// fetching every match in the sort order and grouping the hits by the value
+// of the collapse field. Groups are ordered by their top hit. The matches
+// are counted with the plain query, so the profile of a profiled query only
+// covers the search of the hits. A negative size or from is rejected.
+func (i *Index) searchCollapsed(ctx context.Context, plain, q query.Query, sr search.SearchRequest) (*search.Result, error) {
+	if sr.Size < 0 || sr.From < 0 {
+		return nil, &search.Error{
+			Op:    "search",
+			Index: i.name,
+			Kind:  search.ErrInvalidArgument,
+			Err:   fmt.Errorf("collapse requires a non negative size and from, got size %d and from %d", sr.Size, sr.From),
+		}
+	}
+
+	countReq := bleve.NewSearchRequestOptions(plain, 0, 0, false)
+	if err := countReq.Validate(); err != nil {
+		return nil, err
+	}
+	count, err := i.searchInContext(ctx, "search", countReq)
+	if err != nil {
+		return nil, err
+	}
+	if count.Total > uint64(maxCollapseWindow) {
+		return nil, fmt.Errorf("collapse supports at most %d matches, the query matches %d", maxCollapseWindow, count.Total)
+	}
+
+	requested := containsString(sr.Fields, sr.Collapse.Field)
+	all := sr
+	all.From, all.Size = 0, int(count.Total)
+	if !requested {
+		all.Fields = append(append([]string{}, sr.Fields...), sr.Collapse.Field)
+	}
+	res, err := i.search(ctx, q, all)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		groups []*search.Hit
+		byKey  = make(map[string]*search.Hit)
+	)
+	for _, h := range res.Hits {
+		key := collapseKey(h.Fields[sr.Collapse.Field])
+		if !requested {
+			delete(h.Fields, sr.Collapse.Field)
+			if len(h.Fields) == 0 {
+				h.Fields = nil
+			}
+		}
+
+		top, ok := byKey[key]
+		if !ok {
+			top = &search.Hit{}
+			*top = h
+			top.Group = &search.HitGroup{Key: key, Total: 1}
+			byKey[key] = top
+			groups = append(groups, top)
+			continue
+		}
+		top.Group.Total++
+		if len(top.Group.InnerHits) < sr.Collapse.InnerHits {
+			top.Group.InnerHits = append(top.Group.InnerHits, h)
+		}
+	}
+
+	res.Groups = uint64(len(groups))
+	res.Hits = make([]search.Hit, 0, minInt(sr.Size, len(groups)))
+	for j := sr.From; j < len(groups) && len(res.Hits) < sr.Size; j++ {
+		res.Hits = append(res.Hits, *groups[j])
+	}
+	return res, nil
+}
+
+// collapseKey formats the value of the collapse field. The first value of a
+// multi valued field is used.
+func collapseKey(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		if len(value) == 0 {
+			return ""
+		}
+		return collapseKey(value[0])
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
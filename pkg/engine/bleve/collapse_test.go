@@ -0,0 +1,37 @@
+package bleve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_searchCollapsed_window(t *testing.T) {
+	engine, err := NewEngine(IndexCfg{Name: "base"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, engine.Index("base").Index(ctx, id, map[string]interface{}{"text": "phone", "product": id}))
+	}
+
+	defer func(window int) { maxCollapseWindow = window }(maxCollapseWindow)
+
+	maxCollapseWindow = 3
+	result, err := engine.Index("base").Search(ctx, search.NewQueryMatch("phone").SetField("text"),
+		search.WithCollapse(search.CollapseByField("product")),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Groups)
+
+	maxCollapseWindow = 2
+	_, err = engine.Index("base").Search(ctx, search.NewQueryMatch("phone").SetField("text"),
+		search.WithCollapse(search.CollapseByField("product")),
+	)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, search.ErrInvalidQuery))
+}
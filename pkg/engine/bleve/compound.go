@@ -0,0 +1,433 @@
+package bleve
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+// disMaxQuery matches the documents matching any of the disjuncts and
+// scores them by the best scoring disjunct, adding the scores of the other
+// matching disjuncts multiplied by the tie breaker.
+type disMaxQuery struct {
+	Disjuncts  []query.Query
+	TieBreaker float64
+	BoostVal   *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*disMaxQuery)(nil)
+	_ query.ValidatableQuery = (*disMaxQuery)(nil)
+)
+
+func (q *disMaxQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *disMaxQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *disMaxQuery) Validate() error {
+	return validateQueries(q.Disjuncts...)
+}
+
+func (q *disMaxQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	searchers := make([]ogsearch.Searcher, 0, len(q.Disjuncts))
+	for _, disjunct := range q.Disjuncts {
+		s, err := disjunct.Searcher(i, m, options)
+		if err != nil {
+			closeSearchers(searchers)
+			return nil, err
+		}
+		searchers = append(searchers, s)
+	}
+	if len(searchers) == 0 {
+		return query.NewMatchNoneQuery().Searcher(i, m, options)
+	}
+
+	s := &disMaxSearcher{
+		searchers:  searchers,
+		currs:      make([]*ogsearch.DocumentMatch, len(searchers)),
+		tieBreaker: q.TieBreaker,
+		boost:      q.BoostVal.Value(),
+		options:    options,
+	}
+	s.computeQueryNorm()
+	return s, nil
+}
+
+type disMaxSearcher struct {
+	searchers  []ogsearch.Searcher
+	currs      []*ogsearch.DocumentMatch
+	matching   []int
+	tieBreaker float64
+	boost      float64
+	options    ogsearch.SearcherOptions
+
+	initialized bool
+}
+
+func (s *disMaxSearcher) computeQueryNorm() {
+	var sumOfSquaredWeights float64
+	for _, searcher := range s.searchers {
+		sumOfSquaredWeights += searcher.Weight()
+	}
+	queryNorm := 1.0 / math.Sqrt(sumOfSquaredWeights)
+	for _, searcher := range s.searchers {
+		searcher.SetQueryNorm(queryNorm)
+	}
+}
+
+func (s *disMaxSearcher) initSearchers(ctx *ogsearch.SearchContext) error {
+	for i, searcher := range s.searchers {
+		curr, err := searcher.Next(ctx)
+		if err != nil {
+			return err
+		}
+		s.currs[i] = curr
+	}
+	s.updateMatches()
+	s.initialized = true
+	return nil
+}
+
+// updateMatches collects the searchers positioned on the lowest document.
+func (s *disMaxSearcher) updateMatches() {
+	matching := s.matching[:0]
+	for i, curr := range s.currs {
+		if curr == nil {
+			continue
+		}
+		if len(matching) > 0 {
+			cmp := curr.IndexInternalID.Compare(s.currs[matching[0]].IndexInternalID)
+			if cmp > 0 {
+				continue
+			}
+			if cmp < 0 {
+				matching = matching[:0]
+			}
+		}
+		matching = append(matching, i)
+	}
+	s.matching = matching
+}
+
+func (s *disMaxSearcher) score() *ogsearch.DocumentMatch {
+	var (
+		max, sum float64
+		children []*ogsearch.Explanation
+		locs     []ogsearch.FieldTermLocationMap
+	)
+	for _, i := range s.matching {
+		curr := s.currs[i]
+		sum += curr.Score
+		max = maxFloat(max, curr.Score)
+		if s.options.Explain {
+			children = append(children, curr.Expl)
+		}
+		if curr.Locations != nil {
+			locs = append(locs, curr.Locations)
+		}
+	}
+
+	score := (max + s.tieBreaker*(sum-max)) * s.boost
+
+	rv := s.currs[s.matching[0]]
+	rv.Score = score
+	if s.options.Explain {
+		rv.Expl = &ogsearch.Explanation{
+			Value:    score,
+			Message:  fmt.Sprintf("max plus %v times others of:", s.tieBreaker),
+			Children: children,
+		}
+	}
+	if len(locs) == 1 {
+		rv.Locations = locs[0]
+	} else if len(locs) > 1 {
+		rv.Locations = ogsearch.MergeLocations(locs)
+	}
+	return rv
+}
+
+func (s *disMaxSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	if !s.initialized {
+		if err := s.initSearchers(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.matching) == 0 {
+		return nil, nil
+	}
+
+	rv := s.score()
+	for _, i := range s.matching {
+		if s.currs[i] != rv {
+			ctx.DocumentMatchPool.Put(s.currs[i])
+		}
+		curr, err := s.searchers[i].Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.currs[i] = curr
+	}
+	s.updateMatches()
+	return rv, nil
+}
+
+func (s *disMaxSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	if !s.initialized {
+		if err := s.initSearchers(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for i, searcher := range s.searchers {
+		// exhausted searchers have no current match
+		if s.currs[i] == nil {
+			continue
+		}
+		curr, err := nextAtOrAfter(ctx, searcher, s.currs[i], ID)
+		if err != nil {
+			return nil, err
+		}
+		s.currs[i] = curr
+	}
+	s.updateMatches()
+	return s.Next(ctx)
+}
+
+func (s *disMaxSearcher) Weight() float64 {
+	var rv float64
+	for _, searcher := range s.searchers {
+		rv += searcher.Weight()
+	}
+	return rv
+}
+
+func (s *disMaxSearcher) SetQueryNorm(qnorm float64) {
+	for _, searcher := range s.searchers {
+		searcher.SetQueryNorm(qnorm)
+	}
+}
+
+func (s *disMaxSearcher) Count() uint64 {
+	var sum uint64
+	for _, searcher := range s.searchers {
+		sum += searcher.Count()
+	}
+	return sum
+}
+
+func (s *disMaxSearcher) Close() error {
+	return closeSearchers(s.searchers)
+}
+
+func (s *disMaxSearcher) Min() int {
+	return 0
+}
+
+func (s *disMaxSearcher) DocumentMatchPoolSize() int {
+	rv := len(s.currs)
+	for _, searcher := range s.searchers {
+		rv += searcher.DocumentMatchPoolSize()
+	}
+	return rv
+}
+
+// boostingQuery matches the documents matching the positive query. The
+// score of the documents also matching the negative query is multiplied by
+// the negative boost. Without a negative query it boosts the positive query,
+// which is used for the queries whose bleve searchers ignore their boost.
+type boostingQuery struct {
+	Positive      query.Query
+	Negative      query.Query
+	NegativeBoost float64
+	BoostVal      *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*boostingQuery)(nil)
+	_ query.ValidatableQuery = (*boostingQuery)(nil)
+)
+
+func (q *boostingQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *boostingQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *boostingQuery) Validate() error {
+	if q.Positive == nil {
+		return fmt.Errorf("boosting query requires a positive query")
+	}
+	if q.NegativeBoost < 0 {
+		return fmt.Errorf("boosting query requires a non negative negative boost, got %v", q.NegativeBoost)
+	}
+	return validateQueries(q.Positive, q.Negative)
+}
+
+func (q *boostingQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	positive, err := q.Positive.Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+	s := &boostingSearcher{
+		positive:      positive,
+		negativeBoost: q.NegativeBoost,
+		boost:         q.BoostVal.Value(),
+		options:       options,
+		negDone:       true,
+	}
+	if q.Negative == nil {
+		if s.boost == 1 {
+			return positive, nil
+		}
+		return s, nil
+	}
+
+	negative, err := q.Negative.Searcher(i, m, ogsearch.SearcherOptions{})
+	if err != nil {
+		positive.Close()
+		return nil, err
+	}
+	s.negative, s.negDone = negative, false
+	return s, nil
+}
+
+type boostingSearcher struct {
+	positive      ogsearch.Searcher
+	negative      ogsearch.Searcher
+	negativeBoost float64
+	boost         float64
+	options       ogsearch.SearcherOptions
+
+	negCurr *ogsearch.DocumentMatch
+	negDone bool
+}
+
+func (s *boostingSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.positive.Next(ctx)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.demote(ctx, dm)
+}
+
+func (s *boostingSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.positive.Advance(ctx, ID)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.demote(ctx, dm)
+}
+
+// demote positions the negative searcher on the positive match and applies
+// the negative boost when both match the document.
+func (s *boostingSearcher) demote(ctx *ogsearch.SearchContext, dm *ogsearch.DocumentMatch) (*ogsearch.DocumentMatch, error) {
+	if !s.negDone && (s.negCurr == nil || s.negCurr.IndexInternalID.Compare(dm.IndexInternalID) < 0) {
+		negCurr, err := nextAtOrAfter(ctx, s.negative, s.negCurr, dm.IndexInternalID)
+		if err != nil {
+			return nil, err
+		}
+		s.negCurr, s.negDone = negCurr, negCurr == nil
+	}
+
+	factor := s.boost
+	if s.negCurr != nil && s.negCurr.IndexInternalID.Equals(dm.IndexInternalID) {
+		factor *= s.negativeBoost
+	}
+	if factor == 1 {
+		return dm, nil
+	}
+
+	score := dm.Score * factor
+	if s.options.Explain {
+		dm.Expl = &ogsearch.Explanation{
+			Value:   score,
+			Message: "product of:",
+			Children: []*ogsearch.Explanation{
+				dm.Expl,
+				{Value: factor, Message: "boosting"},
+			},
+		}
+	}
+	dm.Score = score
+	return dm, nil
+}
+
+func (s *boostingSearcher) Weight() float64 {
+	return s.positive.Weight()
+}
+
+func (s *boostingSearcher) SetQueryNorm(qnorm float64) {
+	s.positive.SetQueryNorm(qnorm)
+}
+
+func (s *boostingSearcher) Count() uint64 {
+	return s.positive.Count()
+}
+
+func (s *boostingSearcher) Close() error {
+	if s.negative == nil {
+		return s.positive.Close()
+	}
+	return closeSearchers([]ogsearch.Searcher{s.positive, s.negative})
+}
+
+func (s *boostingSearcher) Min() int {
+	return 0
+}
+
+func (s *boostingSearcher) DocumentMatchPoolSize() int {
+	if s.negative == nil {
+		return s.positive.DocumentMatchPoolSize()
+	}
+	return s.positive.DocumentMatchPoolSize() + s.negative.DocumentMatchPoolSize() + 1
+}
+
+func validateQueries(queries ...query.Query) error {
+	for _, q := range queries {
+		vq, ok := q.(query.ValidatableQuery)
+		if !ok {
+			continue
+		}
+		if err := vq.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func closeSearchers(searchers []ogsearch.Searcher) (rv error) {
+	for _, s := range searchers {
+		if err := s.Close(); err != nil && rv == nil {
+			rv = err
+		}
+	}
+	return rv
+}
+
+// nextAtOrAfter moves the searcher from its current match to the first match
+// at or after the ID. It only relies on Next, as the boolean searcher of
+// bleve skips the match it already read ahead when advanced.
+func nextAtOrAfter(ctx *ogsearch.SearchContext, s ogsearch.Searcher, curr *ogsearch.DocumentMatch, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	for curr == nil || curr.IndexInternalID.Compare(ID) < 0 {
+		if curr != nil {
+			ctx.DocumentMatchPool.Put(curr)
+		}
+		next, err := s.Next(ctx)
+		if err != nil || next == nil {
+			return nil, err
+		}
+		curr = next
+	}
+	return curr, nil
+}
@@ -0,0 +1,50 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+// constantScoreQuery matches the documents of the query with a constant
+// score equal to its boost.
+type constantScoreQuery struct {
+	Query    query.Query
+	BoostVal *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*constantScoreQuery)(nil)
+	_ query.ValidatableQuery = (*constantScoreQuery)(nil)
+)
+
+func (q *constantScoreQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *constantScoreQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *constantScoreQuery) Validate() error {
+	if q.Query == nil {
+		return fmt.Errorf("constant score query requires a query")
+	}
+	return validateQueries(q.Query)
+}
+
+func (q *constantScoreQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	s, err := q.Query.Searcher(i, m, ogsearch.SearcherOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &constantScoreSearcher{
+		Searcher: s,
+		score:    q.BoostVal.Value(),
+		options:  options,
+	}, nil
+}
@@ -2,26 +2,37 @@ package bleve
 
 import (
 	"context"
-	"fmt"
+	"sort"
+	"sync"
 
-	"github.com/blevesearch/bleve"
 	"github.com/jsteenb2/search"
 )
 
 type Engine struct {
-	indices map[string]bleve.Index
+	mu      sync.RWMutex
+	indices map[string]*Index
 }
 
 var _ search.Engine = (*Engine)(nil)
 
 func NewEngine(index IndexCfg, rest ...IndexCfg) (*Engine, error) {
-	setupIndices := make(map[string]bleve.Index)
+	return NewEngineContext(context.Background(), index, rest...)
+}
+
+// NewEngineContext creates an engine, setting up the indices with the
+// context.
+func NewEngineContext(ctx context.Context, index IndexCfg, rest ...IndexCfg) (*Engine, error) {
+	setupIndices := make(map[string]*Index)
 	for _, i := range append(rest, index) {
-		index, err := i.Setup(context.TODO())
+		index, err := i.Setup(ctx)
 		if err != nil {
 			return nil, err
 		}
-		setupIndices[i.Name] = index
+		setupIndices[i.Name] = &Index{
+			name:   i.Name,
+			index:  index,
+			schema: i.Schema,
+		}
 	}
 
 	return &Engine{
@@ -30,25 +41,89 @@ func NewEngine(index IndexCfg, rest ...IndexCfg) (*Engine, error) {
 }
 
 func (e *Engine) Index(name string) search.Index {
-	index, ok := e.indices[name]
-	if !ok {
+	index, err := e.LookupIndex(name)
+	if err != nil {
 		return &Index{
-			err: fmt.Errorf("index does not exist for this engine: %q" + name),
+			name: name,
+			err:  err,
 		}
 	}
-	return &Index{
-		name:  name,
-		index: index,
+	return index
+}
+
+func (e *Engine) LookupIndex(name string) (search.Index, error) {
+	e.mu.RLock()
+	index, ok := e.indices[name]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, &search.Error{Op: "index", Index: name, Kind: search.ErrIndexNotFound}
 	}
+	return index, nil
 }
 
+// Indices returns the indices of the engine sorted by name.
 func (e *Engine) Indices() []search.Index {
+	e.mu.RLock()
 	indices := make([]search.Index, 0, len(e.indices))
-	for name, index := range e.indices {
-		indices = append(indices, &Index{
-			name:  name,
-			index: index,
-		})
+	for _, index := range e.indices {
+		indices = append(indices, index)
 	}
+	e.mu.RUnlock()
+
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i].Name() < indices[j].Name()
+	})
 	return indices
 }
+
+// CreateIndex creates an index kept in memory. Use AddIndex to create an
+// index stored on disk.
+func (e *Engine) CreateIndex(ctx context.Context, name string, schema search.Schema) (search.Index, error) {
+	return e.AddIndex(ctx, IndexCfg{Name: name, Schema: schema})
+}
+
+// AddIndex sets up the index and adds it to the engine.
+func (e *Engine) AddIndex(ctx context.Context, cfg IndexCfg) (search.Index, error) {
+	if _, err := e.LookupIndex(cfg.Name); err == nil {
+		return nil, &search.Error{Op: "create index", Index: cfg.Name, Kind: search.ErrIndexExists}
+	}
+
+	bi, err := cfg.Setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := &Index{
+		name:   cfg.Name,
+		index:  bi,
+		schema: cfg.Schema,
+	}
+
+	e.mu.Lock()
+	_, exists := e.indices[cfg.Name]
+	if !exists {
+		e.indices[cfg.Name] = index
+	}
+	e.mu.Unlock()
+
+	if exists {
+		bi.Close()
+		return nil, &search.Error{Op: "create index", Index: cfg.Name, Kind: search.ErrIndexExists}
+	}
+	return index, nil
+}
+
+func (e *Engine) CloseIndex(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return wrapErr("close index", name, err, nil)
+	}
+
+	e.mu.Lock()
+	index, ok := e.indices[name]
+	delete(e.indices, name)
+	e.mu.Unlock()
+
+	if !ok {
+		return &search.Error{Op: "close index", Index: name, Kind: search.ErrIndexNotFound}
+	}
+	return wrapErr("close index", name, index.index.Close(), search.ErrBackendUnavailable)
+}
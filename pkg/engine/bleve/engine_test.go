@@ -1,6 +1,8 @@
 package bleve_test
 
 import (
+	"context"
+	"errors"
 	"io/ioutil"
 	"os"
 	"path"
@@ -9,9 +11,12 @@ import (
 	"github.com/jsteenb2/search"
 	"github.com/jsteenb2/search/pkg/engine/bleve"
 	searchtest "github.com/jsteenb2/search/testing"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// Running the tests with the race detector requires disabling the pointer
+// checks boltdb fails: go test -race -gcflags=all=-d=checkptr=0
 func Test_Engine(t *testing.T) {
 	initFn := func(t *testing.T) (search.Engine, string, func()) {
 		tempDir := newTempDir(t)
@@ -30,6 +35,79 @@ func Test_Engine(t *testing.T) {
 	searchtest.TestSearchQueries(t, initFn)
 }
 
+func Test_EngineSchema(t *testing.T) {
+	initFn := func(t *testing.T, schema search.Schema) (search.Engine, string, func()) {
+		tempDir := newTempDir(t)
+
+		engine, err := bleve.NewEngine(bleve.IndexCfg{
+			Name:   "base",
+			Path:   path.Join(tempDir, "base.bleve"),
+			Schema: schema,
+		})
+		require.NoError(t, err)
+
+		return engine, "base", func() {
+			defer os.RemoveAll(tempDir)
+		}
+	}
+
+	searchtest.TestSchemas(t, initFn)
+}
+
+func Test_EngineSetupErrors(t *testing.T) {
+	t.Run("index exists", func(t *testing.T) {
+		tempDir := newTempDir(t)
+		defer os.RemoveAll(tempDir)
+
+		cfg := bleve.IndexCfg{
+			Name: "base",
+			Path: path.Join(tempDir, "base.bleve"),
+		}
+		_, err := bleve.NewEngine(cfg)
+		require.NoError(t, err)
+
+		_, err = bleve.NewEngine(cfg)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrIndexExists))
+	})
+
+	t.Run("mapping conflict", func(t *testing.T) {
+		tempDir := newTempDir(t)
+		defer os.RemoveAll(tempDir)
+
+		_, err := bleve.NewEngine(bleve.IndexCfg{
+			Name: "base",
+			Path: path.Join(tempDir, "base.bleve"),
+			Schema: search.Schema{
+				Fields: []search.FieldMapping{
+					*search.NewFieldMapping("title", search.FieldTypeText).SetWeight("popularity"),
+				},
+			},
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrMappingConflict))
+
+		var searchErr *search.Error
+		require.True(t, errors.As(err, &searchErr))
+		assert.Equal(t, "base", searchErr.Index)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		tempDir := newTempDir(t)
+		defer os.RemoveAll(tempDir)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := bleve.NewEngineContext(ctx, bleve.IndexCfg{
+			Name: "base",
+			Path: path.Join(tempDir, "base.bleve"),
+		})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
+
 func newTempDir(t *testing.T) string {
 	t.Helper()
 
@@ -0,0 +1,59 @@
+package bleve
+
+import (
+	"context"
+	"errors"
+
+	"github.com/blevesearch/bleve"
+	"github.com/jsteenb2/search"
+)
+
+// wrapErr maps the error onto the errors of the search package. Errors
+// whose kind can not be derived from the error itself get the kind
+// provided, which depends on the operation.
+func wrapErr(op, index string, err, kind error) error {
+	if err == nil {
+		return nil
+	}
+
+	var searchErr *search.Error
+	if errors.As(err, &searchErr) {
+		return err
+	}
+
+	var bleveErr bleve.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		kind = nil
+	case errors.Is(err, context.DeadlineExceeded):
+		kind = search.ErrTimeout
+	case errors.As(err, &bleveErr):
+		kind = bleveErrKind(bleveErr)
+	case errors.Is(err, search.ErrDocumentNotFound):
+		kind = search.ErrDocumentNotFound
+	}
+
+	return &search.Error{
+		Op:    op,
+		Index: index,
+		Kind:  kind,
+		Err:   err,
+	}
+}
+
+func bleveErrKind(err bleve.Error) error {
+	switch err {
+	case bleve.ErrorIndexPathExists:
+		return search.ErrIndexExists
+	case bleve.ErrorIndexPathDoesNotExist:
+		return search.ErrIndexNotFound
+	case bleve.ErrorEmptyID:
+		return search.ErrInvalidArgument
+	case bleve.ErrorAliasMulti, bleve.ErrorAliasEmpty:
+		return search.ErrInvalidQuery
+	default:
+		// metadata missing or corrupt, unknown storage or index type, index
+		// closed and read inconsistencies
+		return search.ErrBackendUnavailable
+	}
+}
@@ -0,0 +1,123 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/blevesearch/bleve/search/searcher"
+)
+
+// existsQuery matches the documents with any term indexed for the field by
+// enumerating the terms of the field dictionary. Fields with more terms than
+// the disjunction clause limit of bleve fail the search.
+type existsQuery struct {
+	FieldVal string
+	BoostVal *query.Boost
+}
+
+var (
+	_ query.FieldableQuery   = (*existsQuery)(nil)
+	_ query.ValidatableQuery = (*existsQuery)(nil)
+)
+
+func (q *existsQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *existsQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *existsQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *existsQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *existsQuery) Validate() error {
+	if q.FieldVal == "" {
+		return fmt.Errorf("exists query requires a field")
+	}
+	return nil
+}
+
+func (q *existsQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	dict, err := i.FieldDict(q.FieldVal)
+	if err != nil {
+		return nil, err
+	}
+	defer dict.Close()
+
+	var terms []string
+	entry, err := dict.Next()
+	for err == nil && entry != nil {
+		terms = append(terms, entry.Term)
+		// the searcher refuses more terms than the clause limit, so there is
+		// no need to read the rest of the dictionary
+		if limit := searcher.DisjunctionMaxClauseCount; limit > 0 && len(terms) > limit {
+			break
+		}
+		entry, err = dict.Next()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return searcher.NewMatchNoneSearcher(i)
+	}
+
+	s, err := searcher.NewMultiTermSearcher(i, terms, q.FieldVal, 1, options, true)
+	if err != nil {
+		return nil, err
+	}
+	return &constantScoreSearcher{
+		Searcher: s,
+		score:    q.BoostVal.Value(),
+		options:  options,
+	}, nil
+}
+
+// constantScoreSearcher replaces the score of every match with a constant
+// score. Its weight is independent of the wrapped searcher, so the query
+// norm of sibling clauses is not affected by the wrapped query either.
+type constantScoreSearcher struct {
+	ogsearch.Searcher
+	score   float64
+	options ogsearch.SearcherOptions
+}
+
+func (s *constantScoreSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Next(ctx)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.rescore(dm), nil
+}
+
+func (s *constantScoreSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Advance(ctx, ID)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.rescore(dm), nil
+}
+
+func (s *constantScoreSearcher) Weight() float64 {
+	return s.score * s.score
+}
+
+func (s *constantScoreSearcher) SetQueryNorm(float64) {}
+
+func (s *constantScoreSearcher) rescore(dm *ogsearch.DocumentMatch) *ogsearch.DocumentMatch {
+	dm.Score = s.score
+	if s.options.Explain {
+		dm.Expl = &ogsearch.Explanation{Value: s.score, Message: "constant score"}
+	}
+	return dm
+}
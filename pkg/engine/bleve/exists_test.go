@@ -0,0 +1,31 @@
+package bleve
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/search/searcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_existsQuery_clauseLimit(t *testing.T) {
+	idx, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+	require.NoError(t, err)
+	defer idx.Close()
+
+	for id, tag := range map[string]string{"a": "red", "b": "green", "c": "blue"} {
+		require.NoError(t, idx.Index(id, map[string]interface{}{"tag": tag}))
+	}
+
+	defer func(limit int) { searcher.DisjunctionMaxClauseCount = limit }(searcher.DisjunctionMaxClauseCount)
+
+	searcher.DisjunctionMaxClauseCount = 3
+	result, err := idx.Search(bleve.NewSearchRequest(&existsQuery{FieldVal: "tag"}))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Total)
+
+	searcher.DisjunctionMaxClauseCount = 2
+	_, err = idx.Search(bleve.NewSearchRequest(&existsQuery{FieldVal: "tag"}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package bleve
+
+import (
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+// filteredQuery restricts the matches of the query to the documents
+// matching all the filters. The filters are searched separately from the
+// query, so they take no part in the scoring.
+type filteredQuery struct {
+	Query   query.Query
+	Filters []query.Query
+}
+
+var _ query.ValidatableQuery = (*filteredQuery)(nil)
+
+func (q *filteredQuery) Validate() error {
+	if err := validateQueries(q.Query); err != nil {
+		return err
+	}
+	return validateQueries(q.Filters...)
+}
+
+func (q *filteredQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	s, err := q.Query.Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := query.NewConjunctionQuery(q.Filters).Searcher(i, m, ogsearch.SearcherOptions{})
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return &filteredSearcher{
+		Searcher: s,
+		filter:   filter,
+	}, nil
+}
+
+// filteredSearcher leapfrogs the searcher and the filter searcher until
+// both are positioned on the same document.
+type filteredSearcher struct {
+	ogsearch.Searcher
+	filter ogsearch.Searcher
+
+	filterCurr *ogsearch.DocumentMatch
+	filterDone bool
+}
+
+func (s *filteredSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.align(ctx, dm)
+}
+
+func (s *filteredSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Advance(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.align(ctx, dm)
+}
+
+func (s *filteredSearcher) align(ctx *ogsearch.SearchContext, dm *ogsearch.DocumentMatch) (*ogsearch.DocumentMatch, error) {
+	var err error
+	for dm != nil {
+		if !s.filterDone && (s.filterCurr == nil || s.filterCurr.IndexInternalID.Compare(dm.IndexInternalID) < 0) {
+			s.filterCurr, err = nextAtOrAfter(ctx, s.filter, s.filterCurr, dm.IndexInternalID)
+			if err != nil {
+				return nil, err
+			}
+			s.filterDone = s.filterCurr == nil
+		}
+		if s.filterDone {
+			ctx.DocumentMatchPool.Put(dm)
+			return nil, nil
+		}
+		if s.filterCurr.IndexInternalID.Equals(dm.IndexInternalID) {
+			return dm, nil
+		}
+
+		dm, err = nextAtOrAfter(ctx, s.Searcher, dm, s.filterCurr.IndexInternalID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (s *filteredSearcher) Close() error {
+	return closeSearchers([]ogsearch.Searcher{s.Searcher, s.filter})
+}
+
+func (s *filteredSearcher) DocumentMatchPoolSize() int {
+	return s.Searcher.DocumentMatchPoolSize() + s.filter.DocumentMatchPoolSize() + 1
+}
@@ -0,0 +1,143 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/numeric"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+// functionScoreQuery rescores the matches of the query with the score
+// functions, reading the field values from the index.
+type functionScoreQuery struct {
+	Query         query.Query
+	FunctionScore search.FunctionScore
+	BoostVal      *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*functionScoreQuery)(nil)
+	_ query.ValidatableQuery = (*functionScoreQuery)(nil)
+)
+
+func (q *functionScoreQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *functionScoreQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *functionScoreQuery) Validate() error {
+	if q.Query == nil {
+		return fmt.Errorf("function score query requires a query")
+	}
+	if err := q.FunctionScore.Validate(); err != nil {
+		return err
+	}
+	return validateQueries(q.Query)
+}
+
+func (q *functionScoreQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	s, err := q.Query.Searcher(i, m, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for _, f := range q.FunctionScore.Functions {
+		if f.Field != "" {
+			fields = append(fields, f.Field)
+		}
+	}
+
+	return &functionScoreSearcher{
+		Searcher:      s,
+		reader:        i,
+		fields:        fields,
+		functionScore: q.FunctionScore,
+		boost:         q.BoostVal.Value(),
+		options:       options,
+	}, nil
+}
+
+type functionScoreSearcher struct {
+	ogsearch.Searcher
+	reader        index.IndexReader
+	fields        []string
+	functionScore search.FunctionScore
+	boost         float64
+	options       ogsearch.SearcherOptions
+}
+
+func (s *functionScoreSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Next(ctx)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.rescore(dm)
+}
+
+func (s *functionScoreSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	dm, err := s.Searcher.Advance(ctx, ID)
+	if err != nil || dm == nil {
+		return nil, err
+	}
+	return s.rescore(dm)
+}
+
+func (s *functionScoreSearcher) rescore(dm *ogsearch.DocumentMatch) (*ogsearch.DocumentMatch, error) {
+	values := make(map[string]int64, len(s.fields))
+	if len(s.fields) > 0 {
+		err := s.reader.DocumentVisitFieldTerms(dm.IndexInternalID, s.fields, func(field string, term []byte) {
+			if _, ok := values[field]; ok {
+				return
+			}
+			prefixCoded := numeric.PrefixCoded(term)
+			if shift, err := prefixCoded.Shift(); err != nil || shift != 0 {
+				return
+			}
+			if i64, err := prefixCoded.Int64(); err == nil {
+				values[field] = i64
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	docID, err := s.reader.ExternalID(dm.IndexInternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.functionScore.Rescore(docID, dm.Score, func(field string, date bool) (float64, bool) {
+		i64, ok := values[field]
+		if !ok {
+			return 0, false
+		}
+		if date {
+			return float64(i64), true
+		}
+		return numeric.Int64ToFloat64(i64), true
+	})
+	if err != nil {
+		return nil, err
+	}
+	score *= s.boost
+
+	if s.options.Explain {
+		dm.Expl = &ogsearch.Explanation{
+			Value:    score,
+			Message:  fmt.Sprintf("function score, %s and %s of:", s.functionScore.ScoreMode, s.functionScore.BoostMode),
+			Children: []*ogsearch.Explanation{dm.Expl},
+		}
+	}
+	dm.Score = score
+	return dm, nil
+}
@@ -0,0 +1,159 @@
+package bleve
+
+import (
+	"sort"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/blevesearch/bleve/search/searcher"
+)
+
+// maxFuzzyExpansions is the maximum number of terms a fuzzy term expands to.
+const maxFuzzyExpansions = 50
+
+// transpositionFuzzyQuery is a fuzzy query that counts the transposition of
+// two adjacent characters as a single edit. The fuzzy query provided by bleve
+// only supports the levenshtein distance, where a transposition is two edits.
+// The dictionary of the field is scanned from the prefix, the terms within
+// the fuzziness are found with a bounded edit distance and the closest
+// maxFuzzyExpansions of them are searched.
+type transpositionFuzzyQuery struct {
+	Term      string
+	Prefix    int
+	Fuzziness int
+	FieldVal  string
+	BoostVal  *query.Boost
+}
+
+var _ query.FieldableQuery = (*transpositionFuzzyQuery)(nil)
+
+func (q *transpositionFuzzyQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *transpositionFuzzyQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *transpositionFuzzyQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *transpositionFuzzyQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *transpositionFuzzyQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	field := q.FieldVal
+	if field == "" {
+		field = m.DefaultSearchField()
+	}
+
+	term := []rune(q.Term)
+	prefix := q.Prefix
+	if prefix > len(term) {
+		prefix = len(term)
+	}
+
+	var (
+		dict index.FieldDict
+		err  error
+	)
+	if prefix > 0 {
+		dict, err = i.FieldDictPrefix(field, []byte(string(term[:prefix])))
+	} else {
+		dict, err = i.FieldDict(field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer dict.Close()
+
+	type candidate struct {
+		term     string
+		distance int
+	}
+	var candidates []candidate
+	entry, err := dict.Next()
+	for ; err == nil && entry != nil; entry, err = dict.Next() {
+		if distance, ok := osaDistanceWithin(term, []rune(entry.Term), q.Fuzziness); ok {
+			candidates = append(candidates, candidate{term: entry.Term, distance: distance})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the closest terms when the term expands to too many
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].distance < candidates[b].distance
+	})
+	if len(candidates) > maxFuzzyExpansions {
+		candidates = candidates[:maxFuzzyExpansions]
+	}
+
+	terms := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		terms = append(terms, c.term)
+	}
+	return searcher.NewMultiTermSearcher(i, terms, field, q.BoostVal.Value(), options, true)
+}
+
+// osaDistanceWithin returns the optimal string alignment distance of a and
+// b, which is the levenshtein distance extended with transpositions of
+// adjacent characters, when it is at most max. The computation stops as soon
+// as the distance is known to exceed max, which rejects most terms of a
+// dictionary after a few characters.
+func osaDistanceWithin(a, b []rune, max int) (int, bool) {
+	if absInt(len(a)-len(b)) > max {
+		return 0, false
+	}
+
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	var prevMin int
+	for i := 1; i <= len(a); i++ {
+		rowMin := rows[i][0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := minInt(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = minInt(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+			rowMin = minInt(rowMin, d)
+		}
+		// the cells of a row are at least the minimum of the previous row
+		// or, through a transposition, one more than the row before it
+		if rowMin > max && prevMin >= max {
+			return 0, false
+		}
+		prevMin = rowMin
+	}
+
+	distance := rows[len(a)][len(b)]
+	return distance, distance <= max
+}
+
+func minInt(first int, rest ...int) int {
+	min := first
+	for _, v := range rest {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
@@ -0,0 +1,79 @@
+package bleve
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_osaDistanceWithin(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		max      int
+		distance int
+		ok       bool
+	}{
+		{a: "help", b: "help", max: 0, distance: 0, ok: true},
+		{a: "help", b: "jelp", max: 1, distance: 1, ok: true},
+		{a: "help", b: "hlep", max: 1, distance: 1, ok: true},
+		{a: "help", b: "helpers", max: 2, ok: false},
+		{a: "help", b: "xyzw", max: 2, ok: false},
+		{a: "", b: "ab", max: 2, distance: 2, ok: true},
+	}
+	for _, tt := range tests {
+		distance, ok := osaDistanceWithin([]rune(tt.a), []rune(tt.b), tt.max)
+		assert.Equal(t, tt.ok, ok, "%s %s", tt.a, tt.b)
+		if tt.ok {
+			assert.Equal(t, tt.distance, distance, "%s %s", tt.a, tt.b)
+		}
+	}
+
+	t.Run("matches the unbounded distance", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		word := func() []rune {
+			w := make([]rune, r.Intn(8))
+			for i := range w {
+				w[i] = rune('a' + r.Intn(4))
+			}
+			return w
+		}
+
+		for i := 0; i < 2000; i++ {
+			a, b, max := word(), word(), r.Intn(4)
+			expected := osaDistance(a, b)
+
+			distance, ok := osaDistanceWithin(a, b, max)
+			assert.Equal(t, expected <= max, ok, "%s %s %d", string(a), string(b), max)
+			if ok {
+				assert.Equal(t, expected, distance, "%s %s", string(a), string(b))
+			}
+		}
+	})
+}
+
+func osaDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := minInt(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = minInt(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
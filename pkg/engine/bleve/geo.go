@@ -0,0 +1,121 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/geo"
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/numeric"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/blevesearch/bleve/search/searcher"
+	"github.com/jsteenb2/search"
+)
+
+// geoPolygonQuery matches the points within the bounding box of the polygon
+// and then filters out the points that fall outside of the polygon itself.
+type geoPolygonQuery struct {
+	Points   []search.GeoPoint
+	FieldVal string
+	BoostVal *query.Boost
+}
+
+var (
+	_ query.FieldableQuery   = (*geoPolygonQuery)(nil)
+	_ query.ValidatableQuery = (*geoPolygonQuery)(nil)
+)
+
+func (q *geoPolygonQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *geoPolygonQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *geoPolygonQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *geoPolygonQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *geoPolygonQuery) Validate() error {
+	if len(q.Points) < 3 {
+		return fmt.Errorf("geo polygon requires at least 3 points, got %d", len(q.Points))
+	}
+	return nil
+}
+
+func (q *geoPolygonQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	field := q.FieldVal
+	if field == "" {
+		field = m.DefaultSearchField()
+	}
+
+	minLon, minLat := q.Points[0].Lon, q.Points[0].Lat
+	maxLon, maxLat := minLon, minLat
+	for _, p := range q.Points[1:] {
+		minLon, maxLon = minFloat(minLon, p.Lon), maxFloat(maxLon, p.Lon)
+		minLat, maxLat = minFloat(minLat, p.Lat), maxFloat(maxLat, p.Lat)
+	}
+
+	boxSearcher, err := searcher.NewGeoBoundingBoxSearcher(i, minLon, minLat, maxLon, maxLat, field, q.BoostVal.Value(), options, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return searcher.NewFilteringSearcher(boxSearcher, func(d *ogsearch.DocumentMatch) bool {
+		// multi valued fields match when any of their points is inside
+		var inside bool
+		err := i.DocumentVisitFieldTerms(d.IndexInternalID, []string{field}, func(_ string, term []byte) {
+			if inside {
+				return
+			}
+			prefixCoded := numeric.PrefixCoded(term)
+			if shift, err := prefixCoded.Shift(); err != nil || shift != 0 {
+				return
+			}
+			i64, err := prefixCoded.Int64()
+			if err != nil {
+				return
+			}
+			point := search.NewGeoPoint(geo.MortonUnhashLat(uint64(i64)), geo.MortonUnhashLon(uint64(i64)))
+			inside = polygonContains(q.Points, point)
+		})
+		return err == nil && inside
+	}), nil
+}
+
+// polygonContains casts a ray from the point and counts the edges of the
+// polygon it crosses, an odd count means the point is inside.
+func polygonContains(polygon []search.GeoPoint, p search.GeoPoint) bool {
+	var inside bool
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > p.Lat) == (b.Lat > p.Lat) {
+			continue
+		}
+		if p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -2,31 +2,71 @@ package bleve
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/blevesearch/bleve"
 	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/numeric"
 	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
 	"github.com/jsteenb2/search"
 )
 
 type IndexCfg struct {
-	Name    string
+	Name string
+	// Path is the directory of the index. Indices without a path are kept
+	// in memory.
 	Path    string
 	Mapping mapping.IndexMapping
+	Schema  search.Schema
 }
 
 func (i *IndexCfg) Setup(ctx context.Context) (bleve.Index, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, wrapErr("setup", i.Name, err, nil)
+	}
+
 	indexMapping := i.Mapping
 	if indexMapping == nil {
 		indexMapping = bleve.NewIndexMapping()
 	}
-	return bleve.New(i.Path, indexMapping)
+
+	if len(i.Schema.Analyzers) > 0 || len(i.Schema.Fields) > 0 {
+		im, ok := indexMapping.(*mapping.IndexMappingImpl)
+		if !ok {
+			err := fmt.Errorf("schema can not be applied to mapping of type %T", indexMapping)
+			return nil, wrapErr("setup", i.Name, err, search.ErrMappingConflict)
+		}
+		if err := applySchema(im, i.Schema); err != nil {
+			return nil, wrapErr("setup", i.Name, err, search.ErrMappingConflict)
+		}
+	}
+	if err := indexMapping.Validate(); err != nil {
+		return nil, wrapErr("setup", i.Name, err, search.ErrMappingConflict)
+	}
+
+	var (
+		index bleve.Index
+		err   error
+	)
+	if i.Path == "" {
+		index, err = bleve.NewMemOnly(indexMapping)
+	} else {
+		index, err = bleve.New(i.Path, indexMapping)
+	}
+	if err != nil {
+		return nil, wrapErr("setup", i.Name, err, search.ErrBackendUnavailable)
+	}
+	return index, nil
 }
 
 type Index struct {
-	name  string
-	index bleve.Index
-	err   error
+	name   string
+	index  bleve.Index
+	schema search.Schema
+	err    error
 }
 
 var _ search.Index = (*Index)(nil)
@@ -39,28 +79,187 @@ func (i *Index) Index(ctx context.Context, id string, data interface{}) error {
 	if i.err != nil {
 		return i.err
 	}
+	if err := i.ctxErr(ctx, "index"); err != nil {
+		return err
+	}
+
+	data, err := i.schema.RouteLanguages(data)
+	if err != nil {
+		return wrapErr("index", i.name, err, search.ErrMappingConflict)
+	}
+	return wrapErr("index", i.name, i.index.Index(id, data), search.ErrBackendUnavailable)
+}
+
+// batchSize is the number of documents applied to the index at once by
+// IndexBatch. The context is checked between documents and batches.
+const batchSize = 100
+
+// IndexBatch applies the documents in batches of batchSize. A batch is
+// applied as a whole, so when the context is done the batches applied before
+// stay indexed while the documents of the remaining batches are not.
+func (i *Index) IndexBatch(ctx context.Context, docs ...search.Document) error {
+	if i.err != nil {
+		return i.err
+	}
 
-	return i.index.Index(id, data)
+	for len(docs) > 0 {
+		n := minInt(batchSize, len(docs))
+		b := i.index.NewBatch()
+		for _, doc := range docs[:n] {
+			if err := i.ctxErr(ctx, "batch"); err != nil {
+				return err
+			}
+			data, err := i.schema.RouteLanguages(doc.Data)
+			if err != nil {
+				return wrapErr("batch", i.name, err, search.ErrMappingConflict)
+			}
+			if err := b.Index(doc.ID, data); err != nil {
+				return wrapErr("batch", i.name, err, search.ErrMappingConflict)
+			}
+		}
+
+		if err := i.ctxErr(ctx, "batch"); err != nil {
+			return err
+		}
+		if err := i.index.Batch(b); err != nil {
+			return wrapErr("batch", i.name, err, search.ErrBackendUnavailable)
+		}
+		docs = docs[n:]
+	}
+	return nil
 }
 
-func (i *Index) Search(ctx context.Context, q search.Query) (*search.Result, error) {
+func (i *Index) Search(ctx context.Context, q search.Query, opts ...search.SearchOptFn) (*search.Result, error) {
 	if i.err != nil {
 		return nil, i.err
 	}
+	if err := i.ctxErr(ctx, "search"); err != nil {
+		return nil, err
+	}
+
+	sr := search.NewSearchRequest(opts...)
+
+	start := time.Now()
+	bq := convertQuery(q)
+	plain := bq
+	var profile *search.Profile
+	if sr.Profile {
+		// profiling rewrites the clauses of the query in place
+		plain = convertQuery(q)
+		var clause *search.ClauseProfile
+		bq, clause = profileQuery(bq)
+		profile = &search.Profile{Query: clause}
+	}
+	converted := time.Now()
 
-	req := bleve.NewSearchRequest(convertQuery(q))
+	var (
+		result *search.Result
+		err    error
+	)
+	if sr.Collapse != nil {
+		result, err = i.searchCollapsed(ctx, plain, bq, sr)
+	} else {
+		result, err = i.search(ctx, bq, sr)
+	}
+	if err != nil {
+		return nil, wrapErr("search", i.name, err, search.ErrInvalidQuery)
+	}
+	if profile != nil {
+		profile.Conversion = converted.Sub(start)
+		profile.Backend = time.Since(converted)
+		result.Profile = profile
+	}
+
+	if result.Total < sr.DidYouMeanBelow {
+		result.DidYouMean, err = i.didYouMean(ctx, q)
+		if err != nil {
+			return nil, wrapErr("search", i.name, err, search.ErrInvalidQuery)
+		}
+	}
+	return result, nil
+}
+
+func (i *Index) search(ctx context.Context, q query.Query, sr search.SearchRequest) (*search.Result, error) {
+	req, err := newSearchRequest(q, sr)
+	if err != nil {
+		return nil, err
+	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	res, err := i.index.Search(req)
+	res, err := i.searchInContext(ctx, "search", req)
 	if err != nil {
 		return nil, err
 	}
-	return convertSearchResult(res), nil
+	return convertSearchResult(res, sr), nil
 }
 
-func convertSearchResult(r *bleve.SearchResult) *search.Result {
+// searchInContext searches the index. Errors of bleve other than context
+// errors come from building the searchers of the query, so they are
+// reported as invalid queries.
+func (i *Index) searchInContext(ctx context.Context, op string, req *bleve.SearchRequest) (*bleve.SearchResult, error) {
+	res, err := i.index.SearchInContext(ctx, req)
+	if err != nil {
+		if ctxErr := i.ctxErr(ctx, op); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, wrapErr(op, i.name, err, search.ErrInvalidQuery)
+	}
+	return res, nil
+}
+
+// ctxErr wraps the error of a done context.
+func (i *Index) ctxErr(ctx context.Context, op string) error {
+	return wrapErr(op, i.name, ctx.Err(), nil)
+}
+
+func newSearchRequest(q query.Query, sr search.SearchRequest) (*bleve.SearchRequest, error) {
+	sorts := sr.Sort
+	if len(sorts) == 0 {
+		if len(sr.After) == 0 {
+			req := bleve.NewSearchRequestOptions(q, sr.Size, sr.From, sr.Explain)
+			req.Fields = sr.Fields
+			return req, nil
+		}
+		sorts = []search.Sort{search.SortByScore()}
+	}
+
+	order := make(ogsearch.SortOrder, 0, len(sorts))
+	for _, s := range sorts {
+		switch s.Type {
+		case search.SortTypeScore:
+			order = append(order, &ogsearch.SortScore{Desc: s.Desc})
+		case search.SortTypeField:
+			order = append(order, &ogsearch.SortField{Field: s.Field, Desc: s.Desc})
+		case search.SortTypeGeoDistance:
+			geoSort, err := ogsearch.NewSortGeoDistance(s.Field, s.Unit, s.Point.Lon, s.Point.Lat, s.Desc)
+			if err != nil {
+				return nil, err
+			}
+			order = append(order, geoSort)
+		case search.SortTypeID:
+			order = append(order, &ogsearch.SortDocID{Desc: s.Desc})
+		default:
+			return nil, fmt.Errorf("unexpected sort: %s", s.Type)
+		}
+	}
+
+	if len(sr.After) > 0 {
+		after, err := newSearchAfterQuery(q, order, sorts, sr.After)
+		if err != nil {
+			return nil, err
+		}
+		q = after
+	}
+
+	req := bleve.NewSearchRequestOptions(q, sr.Size, sr.From, sr.Explain)
+	req.Fields = sr.Fields
+	req.SortByCustom(order)
+	return req, nil
+}
+
+func convertSearchResult(r *bleve.SearchResult, sr search.SearchRequest) *search.Result {
 	s := &search.Result{
 		MaxScore: r.MaxScore,
 		Took:     r.Took,
@@ -81,13 +280,40 @@ func convertSearchResult(r *bleve.SearchResult) *search.Result {
 			ID:          h.ID,
 			Score:       h.Score,
 			Explanation: convertExplanation(h.Expl),
-			Sort:        h.Sort,
+			Sort:        convertSortValues(h.Sort, h.Score, sr.Sort),
 			Fields:      h.Fields,
 		})
 	}
 	return s
 }
 
+// convertSortValues decodes the numeric geo distance sort values and
+// replaces the score placeholders with the score, all other values are
+// returned as is.
+func convertSortValues(values []string, score float64, sorts []search.Sort) []string {
+	if len(sorts) == 0 {
+		sorts = []search.Sort{search.SortByScore()}
+	}
+	// bleve shares the values of score sorts between hits
+	values = append([]string(nil), values...)
+	for i, s := range sorts {
+		if i >= len(values) {
+			break
+		}
+		switch s.Type {
+		case search.SortTypeScore:
+			values[i] = strconv.FormatFloat(score, 'g', -1, 64)
+		case search.SortTypeGeoDistance:
+			i64, err := numeric.PrefixCoded(values[i]).Int64()
+			if err != nil {
+				continue
+			}
+			values[i] = strconv.FormatFloat(numeric.Int64ToFloat64(i64), 'f', -1, 64)
+		}
+	}
+	return values
+}
+
 func convertExplanation(ex *ogsearch.Explanation) *search.Explanation {
 	if ex == nil {
 		return nil
@@ -101,7 +327,7 @@ func convertExplanation(ex *ogsearch.Explanation) *search.Explanation {
 		return newEx
 	}
 
-	newEx.Children = make([]*search.Explanation, len(newEx.Children))
+	newEx.Children = make([]*search.Explanation, 0, len(ex.Children))
 	for _, chExpl := range ex.Children {
 		newEx.Children = append(newEx.Children, convertExplanation(chExpl))
 	}
@@ -0,0 +1,90 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+)
+
+// percentMatchQuery is a match query requiring a percentage of the analyzed
+// terms to match. The number of required terms is rounded down, but at
+// least one term must always match.
+type percentMatchQuery struct {
+	Match     string
+	Analyzer  string
+	Percent   int
+	Prefix    int
+	Fuzziness int
+	FieldVal  string
+	BoostVal  *query.Boost
+}
+
+var _ query.FieldableQuery = (*percentMatchQuery)(nil)
+
+func (q *percentMatchQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *percentMatchQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *percentMatchQuery) SetField(f string) {
+	q.FieldVal = f
+}
+
+func (q *percentMatchQuery) Field() string {
+	return q.FieldVal
+}
+
+func (q *percentMatchQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	field := q.FieldVal
+	if field == "" {
+		field = m.DefaultSearchField()
+	}
+
+	analyzerName := q.Analyzer
+	if analyzerName == "" {
+		analyzerName = m.AnalyzerNameForPath(field)
+	}
+	analyzer := m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+
+	tokens := analyzer.Analyze([]byte(q.Match))
+	if len(tokens) == 0 {
+		return query.NewMatchNoneQuery().Searcher(i, m, options)
+	}
+
+	tqs := make([]query.Query, 0, len(tokens))
+	for _, token := range tokens {
+		if q.Fuzziness > 0 {
+			fq := query.NewFuzzyQuery(string(token.Term))
+			fq.SetFuzziness(q.Fuzziness)
+			fq.SetPrefix(q.Prefix)
+			fq.SetField(field)
+			fq.SetBoost(q.BoostVal.Value())
+			tqs = append(tqs, fq)
+			continue
+		}
+		tq := query.NewTermQuery(string(token.Term))
+		tq.SetField(field)
+		tq.SetBoost(q.BoostVal.Value())
+		tqs = append(tqs, tq)
+	}
+
+	min := len(tqs) * q.Percent / 100
+	if min < 1 {
+		min = 1
+	}
+
+	dq := query.NewDisjunctionQuery(tqs)
+	dq.SetMin(float64(min))
+	dq.SetBoost(q.BoostVal.Value())
+	return dq.Searcher(i, m, options)
+}
@@ -0,0 +1,177 @@
+package bleve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/document"
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+// moreLikeThisQuery selects the most significant terms of the liked
+// documents and texts by tf-idf and searches for them, excluding the liked
+// documents.
+type moreLikeThisQuery struct {
+	MoreLikeThis search.MoreLikeThis
+	BoostVal     *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*moreLikeThisQuery)(nil)
+	_ query.ValidatableQuery = (*moreLikeThisQuery)(nil)
+)
+
+type mltTerm struct {
+	field string
+	term  string
+	score float64
+}
+
+func (q *moreLikeThisQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *moreLikeThisQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *moreLikeThisQuery) Validate() error {
+	if len(q.MoreLikeThis.Fields) == 0 {
+		return fmt.Errorf("more like this query requires at least one field")
+	}
+	if len(q.MoreLikeThis.IDs) == 0 && len(q.MoreLikeThis.Like) == 0 {
+		return fmt.Errorf("more like this query requires a document id or text to like")
+	}
+	return nil
+}
+
+func (q *moreLikeThisQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	terms, err := q.selectTerms(i, m)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return query.NewMatchNoneQuery().Searcher(i, m, options)
+	}
+
+	disjuncts := make([]query.Query, 0, len(terms))
+	for _, t := range terms {
+		tq := query.NewTermQuery(t.term)
+		tq.SetField(t.field)
+		disjuncts = append(disjuncts, tq)
+	}
+
+	bq := query.NewBooleanQuery(nil, disjuncts, nil)
+	if len(q.MoreLikeThis.IDs) > 0 {
+		bq.AddMustNot(query.NewDocIDQuery(q.MoreLikeThis.IDs))
+	}
+	bq.SetBoost(q.BoostVal.Value())
+	return bq.Searcher(i, m, options)
+}
+
+// selectTerms scores the terms of the likes per field by their term
+// frequency in the likes times their inverse document frequency in the index.
+func (q *moreLikeThisQuery) selectTerms(i index.IndexReader, m mapping.IndexMapping) ([]mltTerm, error) {
+	mlt := q.MoreLikeThis
+
+	texts := make(map[string][]string, len(mlt.Fields))
+	for _, field := range mlt.Fields {
+		texts[field] = append(texts[field], mlt.Like...)
+	}
+	for _, id := range mlt.IDs {
+		doc, err := i.Document(id)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			return nil, fmt.Errorf("more like this document %q: %w", id, search.ErrDocumentNotFound)
+		}
+		for _, f := range doc.Fields {
+			tf, ok := f.(*document.TextField)
+			if !ok {
+				continue
+			}
+			if _, ok := texts[tf.Name()]; ok {
+				texts[tf.Name()] = append(texts[tf.Name()], string(tf.Value()))
+			}
+		}
+	}
+
+	stopWords := make(map[string]bool, len(mlt.StopWords))
+	for _, w := range mlt.StopWords {
+		stopWords[strings.ToLower(w)] = true
+	}
+
+	docCount, err := i.DocCount()
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []mltTerm
+	for _, field := range mlt.Fields {
+		analyzerName := m.AnalyzerNameForPath(field)
+		analyzer := m.AnalyzerNamed(analyzerName)
+		if analyzer == nil {
+			return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+		}
+
+		freqs := make(map[string]int)
+		for _, text := range texts[field] {
+			for _, token := range analyzer.Analyze([]byte(text)) {
+				term := string(token.Term)
+				if !stopWords[strings.ToLower(term)] {
+					freqs[term]++
+				}
+			}
+		}
+
+		for term, freq := range freqs {
+			if freq < mlt.MinTermFreq {
+				continue
+			}
+			docFreq, err := termDocFreq(i, field, term)
+			if err != nil {
+				return nil, err
+			}
+			if docFreq == 0 || docFreq < uint64(mlt.MinDocFreq) {
+				continue
+			}
+			idf := 1 + math.Log(float64(docCount)/float64(docFreq+1))
+			terms = append(terms, mltTerm{
+				field: field,
+				term:  term,
+				score: float64(freq) * idf,
+			})
+		}
+	}
+
+	sort.Slice(terms, func(a, b int) bool {
+		if terms[a].score != terms[b].score {
+			return terms[a].score > terms[b].score
+		}
+		if terms[a].field != terms[b].field {
+			return terms[a].field < terms[b].field
+		}
+		return terms[a].term < terms[b].term
+	})
+	if mlt.MaxQueryTerms > 0 && len(terms) > mlt.MaxQueryTerms {
+		terms = terms[:mlt.MaxQueryTerms]
+	}
+	return terms, nil
+}
+
+func termDocFreq(i index.IndexReader, field, term string) (uint64, error) {
+	reader, err := i.TermFieldReader([]byte(term), field, false, false, false)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return reader.Count(), nil
+}
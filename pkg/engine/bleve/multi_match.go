@@ -0,0 +1,294 @@
+package bleve
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+// maxPrefixExpansions limits the number of terms the last term of a phrase
+// prefix expands into.
+const maxPrefixExpansions = 50
+
+// multiMatchQuery matches the text against several fields. The per field
+// queries depend on the analyzers of the fields, so they are built when the
+// searcher is created.
+type multiMatchQuery struct {
+	Match      string
+	Fields     []string
+	Type       search.MultiMatchType
+	TieBreaker float64
+	Analyzer   string
+	Operator   search.QueryOperator
+	Percent    int
+	Fuzziness  int
+	BoostVal   *query.Boost
+}
+
+var (
+	_ query.BoostableQuery   = (*multiMatchQuery)(nil)
+	_ query.ValidatableQuery = (*multiMatchQuery)(nil)
+)
+
+type fieldBoost struct {
+	field string
+	boost float64
+}
+
+func (q *multiMatchQuery) SetBoost(b float64) {
+	boost := query.Boost(b)
+	q.BoostVal = &boost
+}
+
+func (q *multiMatchQuery) Boost() float64 {
+	return q.BoostVal.Value()
+}
+
+func (q *multiMatchQuery) Validate() error {
+	if len(q.Fields) == 0 {
+		return fmt.Errorf("multi match query requires at least one field")
+	}
+	if int(q.Type) > int(search.MultiMatchPhrasePrefix) {
+		return fmt.Errorf("unexpected multi match type: %d", q.Type)
+	}
+	_, err := q.fieldBoosts()
+	return err
+}
+
+func (q *multiMatchQuery) fieldBoosts() ([]fieldBoost, error) {
+	fields := make([]fieldBoost, 0, len(q.Fields))
+	for _, f := range q.Fields {
+		field, boost, err := search.ParseFieldBoost(f)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fieldBoost{field: field, boost: boost})
+	}
+	return fields, nil
+}
+
+func (q *multiMatchQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	fields, err := q.fieldBoosts()
+	if err != nil {
+		return nil, err
+	}
+
+	var expanded query.Query
+	switch q.Type {
+	case search.MultiMatchBestFields:
+		expanded = &disMaxQuery{
+			Disjuncts:  q.matchQueries(fields),
+			TieBreaker: q.TieBreaker,
+		}
+	case search.MultiMatchMostFields:
+		expanded = query.NewDisjunctionQuery(q.matchQueries(fields))
+	case search.MultiMatchCrossFields:
+		expanded, err = q.crossFieldsQuery(m, fields)
+	case search.MultiMatchPhrase, search.MultiMatchPhrasePrefix:
+		expanded, err = q.phraseQuery(i, m, fields)
+	default:
+		err = fmt.Errorf("unexpected multi match type: %d", q.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return (&boostingQuery{
+		Positive: expanded,
+		BoostVal: q.BoostVal,
+	}).Searcher(i, m, options)
+}
+
+func (q *multiMatchQuery) matchQueries(fields []fieldBoost) []query.Query {
+	queries := make([]query.Query, 0, len(fields))
+	for _, f := range fields {
+		if q.Operator == search.MatchQueryOperatorPercent {
+			pq := &percentMatchQuery{
+				Match:     q.Match,
+				Analyzer:  q.Analyzer,
+				Percent:   q.Percent,
+				Fuzziness: q.Fuzziness,
+				FieldVal:  f.field,
+			}
+			pq.SetBoost(f.boost)
+			queries = append(queries, pq)
+			continue
+		}
+
+		mq := query.NewMatchQuery(q.Match)
+		mq.SetField(f.field)
+		mq.SetBoost(f.boost)
+		mq.Analyzer = q.Analyzer
+		mq.Operator = query.MatchQueryOperator(q.Operator)
+		mq.SetFuzziness(q.Fuzziness)
+		queries = append(queries, mq)
+	}
+	return queries
+}
+
+// crossFieldsQuery scores each analyzed term by the best field it occurs
+// in. The operator applies to the terms rather than to the fields.
+func (q *multiMatchQuery) crossFieldsQuery(m mapping.IndexMapping, fields []fieldBoost) (query.Query, error) {
+	var (
+		positions [][]query.Query
+		offset    = -1
+	)
+	for _, f := range fields {
+		tokens, err := q.analyze(m, f.field, q.Match)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			if offset < 0 {
+				offset = token.Position
+			}
+			pos := token.Position - offset
+			for len(positions) <= pos {
+				positions = append(positions, nil)
+			}
+
+			if q.Fuzziness > 0 {
+				fq := query.NewFuzzyQuery(string(token.Term))
+				fq.SetFuzziness(q.Fuzziness)
+				fq.SetField(f.field)
+				fq.SetBoost(f.boost)
+				positions[pos] = append(positions[pos], fq)
+				continue
+			}
+			tq := query.NewTermQuery(string(token.Term))
+			tq.SetField(f.field)
+			tq.SetBoost(f.boost)
+			positions[pos] = append(positions[pos], tq)
+		}
+	}
+
+	terms := make([]query.Query, 0, len(positions))
+	for _, disjuncts := range positions {
+		if len(disjuncts) == 0 {
+			continue
+		}
+		terms = append(terms, &disMaxQuery{
+			Disjuncts:  disjuncts,
+			TieBreaker: q.TieBreaker,
+		})
+	}
+	if len(terms) == 0 {
+		return query.NewMatchNoneQuery(), nil
+	}
+
+	switch q.Operator {
+	case search.MatchQueryOperatorAnd:
+		return query.NewConjunctionQuery(terms), nil
+	case search.MatchQueryOperatorPercent:
+		min := len(terms) * q.Percent / 100
+		if min < 1 {
+			min = 1
+		}
+		dq := query.NewDisjunctionQuery(terms)
+		dq.SetMin(float64(min))
+		return dq, nil
+	default:
+		return query.NewDisjunctionQuery(terms), nil
+	}
+}
+
+func (q *multiMatchQuery) phraseQuery(i index.IndexReader, m mapping.IndexMapping, fields []fieldBoost) (query.Query, error) {
+	disjuncts := make([]query.Query, 0, len(fields))
+	for _, f := range fields {
+		tokens, err := q.analyze(m, f.field, q.Match)
+		if err != nil {
+			return nil, err
+		}
+		phrase := tokensToPhrase(tokens)
+		if len(phrase) == 0 {
+			continue
+		}
+
+		if q.Type == search.MultiMatchPhrasePrefix {
+			last := len(phrase) - 1
+			phrase[last], err = expandPrefixes(i, f.field, phrase[last])
+			if err != nil {
+				return nil, err
+			}
+			if len(phrase[last]) == 0 {
+				continue
+			}
+		}
+
+		pq := query.NewMultiPhraseQuery(phrase, f.field)
+		disjuncts = append(disjuncts, &boostingQuery{
+			Positive: pq,
+			BoostVal: boostPtr(f.boost),
+		})
+	}
+
+	return &disMaxQuery{
+		Disjuncts:  disjuncts,
+		TieBreaker: q.TieBreaker,
+	}, nil
+}
+
+func (q *multiMatchQuery) analyze(m mapping.IndexMapping, field, text string) (analysis.TokenStream, error) {
+	analyzerName := q.Analyzer
+	if analyzerName == "" {
+		analyzerName = m.AnalyzerNameForPath(field)
+	}
+	analyzer := m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+	return analyzer.Analyze([]byte(text)), nil
+}
+
+// tokensToPhrase groups the terms of the tokens by their position.
+func tokensToPhrase(tokens analysis.TokenStream) [][]string {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	first, last := tokens[0].Position, tokens[0].Position
+	for _, token := range tokens[1:] {
+		first = minInt(first, token.Position)
+		if token.Position > last {
+			last = token.Position
+		}
+	}
+
+	phrase := make([][]string, last-first+1)
+	for _, token := range tokens {
+		pos := token.Position - first
+		phrase[pos] = append(phrase[pos], string(token.Term))
+	}
+	return phrase
+}
+
+func expandPrefixes(i index.IndexReader, field string, prefixes []string) ([]string, error) {
+	var terms []string
+	for _, prefix := range prefixes {
+		dict, err := i.FieldDictPrefix(field, []byte(prefix))
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := dict.Next()
+		for err == nil && entry != nil && len(terms) < maxPrefixExpansions {
+			terms = append(terms, entry.Term)
+			entry, err = dict.Next()
+		}
+		dict.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return terms, nil
+}
+
+func boostPtr(b float64) *query.Boost {
+	boost := query.Boost(b)
+	return &boost
+}
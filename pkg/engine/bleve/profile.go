@@ -0,0 +1,181 @@
+package bleve
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/blevesearch/bleve/search/searcher"
+	"github.com/jsteenb2/search"
+)
+
+// profileQuery wraps every clause of the query with a profiled query,
+// replacing the clauses of the compound queries in place.
+func profileQuery(q query.Query) (query.Query, *search.ClauseProfile) {
+	p := &search.ClauseProfile{Description: describeQuery(q)}
+	profileChild := func(child query.Query) query.Query {
+		if child == nil {
+			return nil
+		}
+		pq, cp := profileQuery(child)
+		p.Children = append(p.Children, cp)
+		return pq
+	}
+	profileChildren := func(children []query.Query) {
+		for i, child := range children {
+			children[i] = profileChild(child)
+		}
+	}
+
+	switch q := q.(type) {
+	case *query.BooleanQuery:
+		q.Must = profileChild(q.Must)
+		q.Should = profileChild(q.Should)
+		q.MustNot = profileChild(q.MustNot)
+	case *query.ConjunctionQuery:
+		profileChildren(q.Conjuncts)
+	case *query.DisjunctionQuery:
+		profileChildren(q.Disjuncts)
+	case *boostingQuery:
+		q.Positive = profileChild(q.Positive)
+		q.Negative = profileChild(q.Negative)
+	case *constantScoreQuery:
+		q.Query = profileChild(q.Query)
+	case *disMaxQuery:
+		profileChildren(q.Disjuncts)
+	case *filteredQuery:
+		q.Query = profileChild(q.Query)
+		profileChildren(q.Filters)
+	case *functionScoreQuery:
+		q.Query = profileChild(q.Query)
+	}
+
+	return &profiledQuery{Query: q, profile: p}, p
+}
+
+func describeQuery(q query.Query) string {
+	field := func(f string) string {
+		if f == "" {
+			return "_all"
+		}
+		return f
+	}
+
+	switch q := q.(type) {
+	case *query.BooleanQuery:
+		return "boolean"
+	case *query.ConjunctionQuery:
+		return "conjunction"
+	case *query.DisjunctionQuery:
+		return "disjunction"
+	case *query.TermQuery:
+		return fmt.Sprintf("term %s:%s", field(q.FieldVal), q.Term)
+	case *query.MatchQuery:
+		return fmt.Sprintf("match %s:%q", field(q.FieldVal), q.Match)
+	case *query.MatchPhraseQuery:
+		return fmt.Sprintf("match phrase %s:%q", field(q.FieldVal), q.MatchPhrase)
+	case *query.PrefixQuery:
+		return fmt.Sprintf("prefix %s:%s", field(q.FieldVal), q.Prefix)
+	case *query.FuzzyQuery:
+		return fmt.Sprintf("fuzzy %s:%s~%d", field(q.FieldVal), q.Term, q.Fuzziness)
+	case *transpositionFuzzyQuery:
+		return fmt.Sprintf("fuzzy %s:%s~%d", field(q.FieldVal), q.Term, q.Fuzziness)
+	case *percentMatchQuery:
+		return fmt.Sprintf("match %s:%q", field(q.FieldVal), q.Match)
+	case *multiMatchQuery:
+		return fmt.Sprintf("multi match %s:%q", strings.Join(q.Fields, ","), q.Match)
+	case *query.MatchAllQuery:
+		return "match all"
+	case *query.MatchNoneQuery:
+		return "match none"
+	case *boostingQuery:
+		return "boosting"
+	case *constantScoreQuery:
+		return "constant score"
+	case *disMaxQuery:
+		return "dis max"
+	case *existsQuery:
+		return fmt.Sprintf("exists %s", field(q.FieldVal))
+	case *filteredQuery:
+		return "filtered"
+	case *functionScoreQuery:
+		return "function score"
+	case *moreLikeThisQuery:
+		return "more like this"
+	default:
+		name := fmt.Sprintf("%T", q)
+		return strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "Query")
+	}
+}
+
+// profiledQuery records the time spent building and iterating the searcher
+// of the query, the documents it matched and the terms it read.
+type profiledQuery struct {
+	Query   query.Query
+	profile *search.ClauseProfile
+}
+
+var _ query.ValidatableQuery = (*profiledQuery)(nil)
+
+func (q *profiledQuery) Validate() error {
+	return validateQueries(q.Query)
+}
+
+func (q *profiledQuery) Searcher(i index.IndexReader, m mapping.IndexMapping, options ogsearch.SearcherOptions) (ogsearch.Searcher, error) {
+	start := time.Now()
+	defer func() {
+		q.profile.Time += time.Since(start)
+	}()
+
+	s, err := q.Query.Searcher(&countingReader{IndexReader: i, profile: q.profile}, m, options)
+	if err != nil {
+		return nil, err
+	}
+	// compound queries drop the clauses matching nothing by the type of
+	// their searcher
+	if _, ok := s.(*searcher.MatchNoneSearcher); ok {
+		return s, nil
+	}
+	return &profiledSearcher{Searcher: s, profile: q.profile}, nil
+}
+
+type profiledSearcher struct {
+	ogsearch.Searcher
+	profile *search.ClauseProfile
+}
+
+func (s *profiledSearcher) Next(ctx *ogsearch.SearchContext) (*ogsearch.DocumentMatch, error) {
+	start := time.Now()
+	dm, err := s.Searcher.Next(ctx)
+	s.record(start, dm)
+	return dm, err
+}
+
+func (s *profiledSearcher) Advance(ctx *ogsearch.SearchContext, ID index.IndexInternalID) (*ogsearch.DocumentMatch, error) {
+	start := time.Now()
+	dm, err := s.Searcher.Advance(ctx, ID)
+	s.record(start, dm)
+	return dm, err
+}
+
+func (s *profiledSearcher) record(start time.Time, dm *ogsearch.DocumentMatch) {
+	s.profile.Time += time.Since(start)
+	if dm != nil {
+		s.profile.DocsVisited++
+	}
+}
+
+// countingReader counts the term readers opened, one per term searched.
+type countingReader struct {
+	index.IndexReader
+	profile *search.ClauseProfile
+}
+
+func (r *countingReader) TermFieldReader(term []byte, field string, includeFreq, includeNorm, includeTermVectors bool) (index.TermFieldReader, error) {
+	r.profile.TermsExpanded++
+	return r.IndexReader.TermFieldReader(term, field, includeFreq, includeNorm, includeTermVectors)
+}
@@ -13,8 +13,45 @@ func convertQuery(q search.Query) query.Query {
 		return newBoolFieldQuery(qp)
 	case search.QueryTypeBoolean:
 		return newBoolQuery(qp)
+	case search.QueryTypeBoosting:
+		return newBoostingQuery(qp)
+	case search.QueryTypeConjunction:
+		return newConjunctionQuery(qp)
+	case search.QueryTypeConstantScore:
+		q := &constantScoreQuery{Query: convertQuery(qp.Must[0])}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
 	case search.QueryTypeDateRange:
 		return newDataRangeQuery(qp)
+	case search.QueryTypeDisjunction:
+		return newDisjunctionQuery(qp)
+	case search.QueryTypeDisMax:
+		return newDisMaxQuery(qp)
+	case search.QueryTypeExists:
+		q := &existsQuery{FieldVal: qp.FieldVal}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
+	case search.QueryTypeFunctionScore:
+		q := &functionScoreQuery{
+			Query:         convertQuery(qp.Must[0]),
+			FunctionScore: qp.FunctionScore,
+		}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
+	case search.QueryTypeFuzzy:
+		return newFuzzyQuery(qp)
+	case search.QueryTypeGeoBoundingBox:
+		return newGeoBoundingBoxQuery(qp)
+	case search.QueryTypeGeoDistance:
+		return newGeoDistanceQuery(qp)
+	case search.QueryTypeGeoPolygon:
+		return newGeoPolygonQuery(qp)
 	case search.QueryTypeIDs:
 		q := query.NewDocIDQuery(qp.Matches)
 		if qp.BoostVal != nil {
@@ -37,6 +74,14 @@ func convertQuery(q search.Query) query.Query {
 		return q
 	case search.QueryTypeMatchPhrase:
 		return newMatchPhraseQuery(qp)
+	case search.QueryTypeMoreLikeThis:
+		q := &moreLikeThisQuery{MoreLikeThis: qp.MoreLikeThis}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
+	case search.QueryTypeMultiMatch:
+		return newMultiMatchQuery(qp)
 	case search.QueryTypeNumericRange:
 		return newNumericRangeQuery(qp)
 	case search.QueryTypePrefix:
@@ -45,6 +90,8 @@ func convertQuery(q search.Query) query.Query {
 		return newTermQuery(qp)
 	case search.QueryTypeTermRange:
 		return newTermRangeQuery(qp)
+	case search.QueryTypeTerms:
+		return newTermsQuery(qp)
 	default:
 		panic("unexpected query type: " + qp.Type.String())
 	}
@@ -61,7 +108,7 @@ func newBoolFieldQuery(qp search.QueryPlan) *query.BoolFieldQuery {
 	return q
 }
 
-func newBoolQuery(qp search.QueryPlan) *query.BooleanQuery {
+func newBoolQuery(qp search.QueryPlan) query.Query {
 	q := bleve.NewBooleanQuery()
 	if qp.BoostVal != nil {
 		q.SetBoost(float64(*qp.BoostVal))
@@ -75,6 +122,87 @@ func newBoolQuery(qp search.QueryPlan) *query.BooleanQuery {
 	for _, mustNot := range qp.MustNot {
 		q.AddMustNot(convertQuery(mustNot))
 	}
+	if qp.MinShould > 0 && q.Should != nil {
+		q.Should.(*query.DisjunctionQuery).SetMin(float64(qp.MinShould))
+	}
+	if len(qp.Filter) == 0 {
+		return q
+	}
+
+	filters := make([]query.Query, 0, len(qp.Filter))
+	for _, filter := range qp.Filter {
+		filters = append(filters, convertQuery(filter))
+	}
+
+	fq := &filteredQuery{
+		Query:   q,
+		Filters: filters,
+	}
+	if q.Must == nil && q.Should == nil && q.MustNot == nil {
+		fq.Query = &constantScoreQuery{
+			Query:    query.NewMatchAllQuery(),
+			BoostVal: q.BoostVal,
+		}
+	}
+	return fq
+}
+
+func newBoostingQuery(qp search.QueryPlan) *boostingQuery {
+	q := &boostingQuery{
+		NegativeBoost: qp.NegativeBoost,
+	}
+	if qp.Positive != nil {
+		q.Positive = convertQuery(qp.Positive)
+	}
+	if qp.Negative != nil {
+		q.Negative = convertQuery(qp.Negative)
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newConjunctionQuery(qp search.QueryPlan) *query.ConjunctionQuery {
+	conjuncts := make([]query.Query, 0, len(qp.Must))
+	for _, must := range qp.Must {
+		conjuncts = append(conjuncts, convertQuery(must))
+	}
+
+	q := query.NewConjunctionQuery(conjuncts)
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newDisjunctionQuery(qp search.QueryPlan) *query.DisjunctionQuery {
+	disjuncts := make([]query.Query, 0, len(qp.Should))
+	for _, should := range qp.Should {
+		disjuncts = append(disjuncts, convertQuery(should))
+	}
+
+	q := query.NewDisjunctionQuery(disjuncts)
+	q.SetMin(float64(qp.MinShould))
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newDisMaxQuery(qp search.QueryPlan) *disMaxQuery {
+	disjuncts := make([]query.Query, 0, len(qp.Should))
+	for _, should := range qp.Should {
+		disjuncts = append(disjuncts, convertQuery(should))
+	}
+
+	q := &disMaxQuery{
+		Disjuncts:  disjuncts,
+		TieBreaker: qp.TieBreaker,
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
 	return q
 }
 
@@ -92,7 +220,83 @@ func newDataRangeQuery(qp search.QueryPlan) *query.DateRangeQuery {
 	return q
 }
 
-func newMatchQuery(qp search.QueryPlan) *query.MatchQuery {
+func newFuzzyQuery(qp search.QueryPlan) query.Query {
+	if qp.Transpositions {
+		q := &transpositionFuzzyQuery{
+			Term:      qp.Matches[0],
+			Prefix:    qp.Prefix,
+			Fuzziness: qp.Fuzziness,
+			FieldVal:  qp.FieldVal,
+		}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
+	}
+
+	q := query.NewFuzzyQuery(qp.Matches[0])
+	q.SetFuzziness(qp.Fuzziness)
+	q.SetPrefix(qp.Prefix)
+	if qp.FieldVal != "" {
+		q.SetField(qp.FieldVal)
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newGeoBoundingBoxQuery(qp search.QueryPlan) *query.GeoBoundingBoxQuery {
+	topLeft, bottomRight := qp.Points[0], qp.Points[1]
+	q := query.NewGeoBoundingBoxQuery(topLeft.Lon, topLeft.Lat, bottomRight.Lon, bottomRight.Lat)
+	if qp.FieldVal != "" {
+		q.SetField(qp.FieldVal)
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newGeoDistanceQuery(qp search.QueryPlan) *query.GeoDistanceQuery {
+	point := qp.Points[0]
+	q := query.NewGeoDistanceQuery(point.Lon, point.Lat, qp.Distance)
+	if qp.FieldVal != "" {
+		q.SetField(qp.FieldVal)
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newGeoPolygonQuery(qp search.QueryPlan) *geoPolygonQuery {
+	q := &geoPolygonQuery{
+		Points:   qp.Points,
+		FieldVal: qp.FieldVal,
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
+func newMatchQuery(qp search.QueryPlan) query.Query {
+	if qp.Operator == search.MatchQueryOperatorPercent {
+		q := &percentMatchQuery{
+			Match:     qp.Matches[0],
+			Analyzer:  qp.Analyzer,
+			Percent:   qp.MinShould,
+			Prefix:    qp.Prefix,
+			Fuzziness: qp.Fuzziness,
+			FieldVal:  qp.FieldVal,
+		}
+		if qp.BoostVal != nil {
+			q.SetBoost(float64(*qp.BoostVal))
+		}
+		return q
+	}
+
 	q := bleve.NewMatchQuery(qp.Matches[0])
 	q.Operator = query.MatchQueryOperator(qp.Operator)
 	if qp.FieldVal != "" {
@@ -127,6 +331,23 @@ func newMatchPhraseQuery(qp search.QueryPlan) *query.MatchPhraseQuery {
 	return q
 }
 
+func newMultiMatchQuery(qp search.QueryPlan) *multiMatchQuery {
+	q := &multiMatchQuery{
+		Match:      qp.Matches[0],
+		Fields:     qp.Fields,
+		Type:       qp.MultiMatch,
+		TieBreaker: qp.TieBreaker,
+		Analyzer:   qp.Analyzer,
+		Operator:   qp.Operator,
+		Percent:    qp.MinShould,
+		Fuzziness:  qp.Fuzziness,
+	}
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
+
 func newNumericRangeQuery(qp search.QueryPlan) *query.NumericRangeQuery {
 	var min *float64
 	if nullMin := search.BoundNullFloat64(qp.Min); nullMin.Valid {
@@ -185,3 +406,20 @@ func newTermRangeQuery(qp search.QueryPlan) *query.TermRangeQuery {
 	}
 	return q
 }
+
+func newTermsQuery(qp search.QueryPlan) *query.DisjunctionQuery {
+	terms := make([]query.Query, 0, len(qp.Matches))
+	for _, term := range qp.Matches {
+		terms = append(terms, &query.TermQuery{
+			Term:     term,
+			FieldVal: qp.FieldVal,
+		})
+	}
+
+	q := query.NewDisjunctionQuery(terms)
+	q.SetMin(float64(qp.MinShould))
+	if qp.BoostVal != nil {
+		q.SetBoost(float64(*qp.BoostVal))
+	}
+	return q
+}
@@ -0,0 +1,211 @@
+package bleve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/index"
+	"github.com/jsteenb2/search"
+)
+
+func (i *Index) SuggestTerms(ctx context.Context, field, text string, opts ...search.TermSuggestOptFn) ([]search.TermSuggestion, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	if err := i.ctxErr(ctx, "suggest terms"); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := i.suggestTerms(ctx, field, "", text, search.NewTermSuggestRequest(opts...))
+	if err != nil {
+		return nil, wrapErr("suggest terms", i.name, err, search.ErrInvalidQuery)
+	}
+	return suggestions, nil
+}
+
+func (i *Index) suggestTerms(ctx context.Context, field, analyzerName, text string, sr search.TermSuggestRequest) ([]search.TermSuggestion, error) {
+	m := i.index.Mapping()
+	if field == "" {
+		field = m.DefaultSearchField()
+	}
+	if analyzerName == "" {
+		analyzerName = m.AnalyzerNameForPath(field)
+	}
+	analyzer := m.AnalyzerNamed(analyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", analyzerName)
+	}
+
+	suggestions := []search.TermSuggestion{}
+	for _, token := range analyzer.Analyze([]byte(text)) {
+		if err := i.ctxErr(ctx, "suggest terms"); err != nil {
+			return nil, err
+		}
+
+		term := string(token.Term)
+		freq, err := i.docFreq(field, term)
+		if err != nil {
+			return nil, err
+		}
+		if freq > 0 {
+			continue
+		}
+
+		corrections, err := i.corrections(field, term, sr)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, search.TermSuggestion{
+			Term:        term,
+			Start:       token.Start,
+			End:         token.End,
+			Corrections: corrections,
+		})
+	}
+	return suggestions, nil
+}
+
+func (i *Index) docFreq(field, term string) (uint64, error) {
+	dict, err := i.index.FieldDictRange(field, []byte(term), []byte(term))
+	if err != nil {
+		return 0, err
+	}
+	defer dict.Close()
+
+	entry, err := dict.Next()
+	if err != nil || entry == nil || entry.Term != term {
+		return 0, err
+	}
+	return entry.Count, nil
+}
+
+// corrections enumerates the terms of the field sharing the prefix of the
+// term and keeps the ones within the maximum edit distance.
+func (i *Index) corrections(field, term string, sr search.TermSuggestRequest) ([]search.TermCorrection, error) {
+	runes := []rune(term)
+	if len(runes) <= sr.Prefix {
+		return []search.TermCorrection{}, nil
+	}
+
+	var (
+		dict index.FieldDict
+		err  error
+	)
+	if sr.Prefix > 0 {
+		dict, err = i.index.FieldDictPrefix(field, []byte(string(runes[:sr.Prefix])))
+	} else {
+		dict, err = i.index.FieldDict(field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer dict.Close()
+
+	corrections := []search.TermCorrection{}
+	entry, err := dict.Next()
+	for ; err == nil && entry != nil; entry, err = dict.Next() {
+		candidate := []rune(entry.Term)
+		if entry.Term == term {
+			continue
+		}
+		distance, ok := osaDistanceWithin(runes, candidate, sr.MaxEdits)
+		if !ok {
+			continue
+		}
+		corrections = append(corrections, search.TermCorrection{
+			Term:     entry.Term,
+			Distance: distance,
+			DocFreq:  entry.Count,
+			Score:    1 - float64(distance)/float64(minInt(len(runes), len(candidate))),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(corrections, func(a, b int) bool {
+		ca, cb := corrections[a], corrections[b]
+		if ca.Score != cb.Score {
+			return ca.Score > cb.Score
+		}
+		if ca.DocFreq != cb.DocFreq {
+			return ca.DocFreq > cb.DocFreq
+		}
+		return ca.Term < cb.Term
+	})
+	if len(corrections) > sr.Size {
+		corrections = corrections[:sr.Size]
+	}
+	return corrections, nil
+}
+
+// didYouMean corrects the text of the match and match phrase queries with
+// the best correction of every misspelled term. Texts without corrections
+// are left out, an empty string is returned when nothing was corrected.
+func (i *Index) didYouMean(ctx context.Context, q search.Query) (string, error) {
+	sr := search.NewTermSuggestRequest(search.WithTermSuggestSize(1))
+
+	var corrected []string
+	for _, m := range collectMatches(q) {
+		suggestions, err := i.suggestTerms(ctx, m.FieldVal, m.Analyzer, m.Matches[0], sr)
+		if err != nil {
+			return "", err
+		}
+		if text, ok := applyCorrections(m.Matches[0], suggestions); ok {
+			corrected = append(corrected, text)
+		}
+	}
+	return strings.Join(corrected, " "), nil
+}
+
+func collectMatches(q search.Query) []search.QueryPlan {
+	if q == nil {
+		return nil
+	}
+
+	qp := q.QueryPlan()
+	switch qp.Type {
+	case search.QueryTypeMatch, search.QueryTypeMatchPhrase:
+		if len(qp.Matches) == 0 {
+			return nil
+		}
+		return []search.QueryPlan{qp}
+	}
+
+	var plans []search.QueryPlan
+	for _, clauses := range [][]search.Query{qp.Must, qp.Should, {qp.Positive}} {
+		for _, clause := range clauses {
+			plans = append(plans, collectMatches(clause)...)
+		}
+	}
+	return plans
+}
+
+// applyCorrections replaces the misspelled terms of the text with their
+// best correction.
+func applyCorrections(text string, suggestions []search.TermSuggestion) (string, bool) {
+	var (
+		b         strings.Builder
+		last      int
+		corrected bool
+	)
+	for _, s := range suggestions {
+		if len(s.Corrections) == 0 || s.Start < last || s.End > len(text) {
+			continue
+		}
+		b.WriteString(text[last:s.Start])
+		b.WriteString(s.Corrections[0].Term)
+		last, corrected = s.End, true
+	}
+	b.WriteString(text[last:])
+	return b.String(), corrected
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
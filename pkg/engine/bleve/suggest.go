@@ -0,0 +1,208 @@
+package bleve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/token/edgengram"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/mapping"
+	ogsearch "github.com/blevesearch/bleve/search"
+	"github.com/blevesearch/bleve/search/query"
+	"github.com/jsteenb2/search"
+)
+
+const (
+	completionAnalyzerName      = "search_completion"
+	completionQueryAnalyzerName = "search_completion_query"
+	completionEdgeNgramName     = "search_completion_edge_ngram"
+
+	// completionSuffix names the companion field holding the edge ngrams of
+	// a completion field.
+	completionSuffix = "_completion"
+
+	// maxCompletionGram is the longest prefix indexed for a completion,
+	// longer prefixes are truncated.
+	maxCompletionGram = 50
+)
+
+// addCompletionAnalyzers registers the analyzers of completion fields. The
+// whole value is lowercased, folded and indexed as all its prefixes, while
+// the prefix searched for is only lowercased and folded.
+func addCompletionAnalyzers(im *mapping.IndexMappingImpl) error {
+	err := im.AddCustomTokenFilter(completionEdgeNgramName, map[string]interface{}{
+		"type": edgengram.Name,
+		"back": false,
+		"min":  1.0,
+		"max":  float64(maxCompletionGram),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = im.AddCustomAnalyzer(completionAnalyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []interface{}{lowercase.Name, asciiFoldingName, completionEdgeNgramName},
+	})
+	if err != nil {
+		return err
+	}
+
+	return im.AddCustomAnalyzer(completionQueryAnalyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []interface{}{lowercase.Name, asciiFoldingName},
+	})
+}
+
+// newCompletionFieldMappings maps the completion field as stored text, to
+// return the suggestions, and as edge ngrams in the companion field.
+func newCompletionFieldMappings(fieldPath string) []*mapping.FieldMapping {
+	text := mapping.NewTextFieldMapping()
+
+	completion := mapping.NewTextFieldMapping()
+	parts := strings.Split(fieldPath, ".")
+	completion.Name = parts[len(parts)-1] + completionSuffix
+	completion.Analyzer = completionAnalyzerName
+	completion.Store = false
+	completion.IncludeInAll = false
+	completion.IncludeTermVectors = false
+
+	return []*mapping.FieldMapping{text, completion}
+}
+
+func (i *Index) Suggest(ctx context.Context, field, prefix string, opts ...search.SuggestOptFn) ([]search.Suggestion, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	if err := i.ctxErr(ctx, "suggest"); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := i.suggest(ctx, field, prefix, search.NewSuggestRequest(opts...))
+	if err != nil {
+		return nil, wrapErr("suggest", i.name, err, search.ErrInvalidQuery)
+	}
+	return suggestions, nil
+}
+
+func (i *Index) suggest(ctx context.Context, field, prefix string, sr search.SuggestRequest) ([]search.Suggestion, error) {
+	fm, ok := i.completionField(field)
+	if !ok {
+		return nil, fmt.Errorf("field %q is not a completion field", field)
+	}
+
+	analyzer := i.index.Mapping().AnalyzerNamed(completionQueryAnalyzerName)
+	if analyzer == nil {
+		return nil, fmt.Errorf("no analyzer named '%s' registered", completionQueryAnalyzerName)
+	}
+	tokens := analyzer.Analyze([]byte(prefix))
+	if len(tokens) == 0 {
+		return []search.Suggestion{}, nil
+	}
+	term := []rune(string(tokens[0].Term))
+	if len(term) > maxCompletionGram {
+		term = term[:maxCompletionGram]
+	}
+
+	var q query.Query
+	if sr.Fuzziness > 0 {
+		q = &transpositionFuzzyQuery{
+			Term:      string(term),
+			Fuzziness: sr.Fuzziness,
+			FieldVal:  field + completionSuffix,
+		}
+	} else {
+		tq := query.NewTermQuery(string(term))
+		tq.SetField(field + completionSuffix)
+		q = tq
+	}
+
+	if len(sr.Contexts) > 0 {
+		filters := make([]query.Query, 0, len(sr.Contexts))
+		for _, c := range sr.Contexts {
+			values := make([]query.Query, 0, len(c.Values))
+			for _, v := range c.Values {
+				mq := query.NewMatchQuery(v)
+				mq.SetField(c.Field)
+				mq.Operator = query.MatchQueryOperatorAnd
+				values = append(values, mq)
+			}
+			filters = append(filters, query.NewDisjunctionQuery(values))
+		}
+		q = &filteredQuery{
+			Query:   q,
+			Filters: filters,
+		}
+	}
+
+	req := bleve.NewSearchRequestOptions(q, sr.Size, 0, false)
+	req.Fields = []string{field}
+	order := ogsearch.SortOrder{&ogsearch.SortScore{Desc: true}, &ogsearch.SortDocID{}}
+	if fm.Weight != "" {
+		req.Fields = append(req.Fields, fm.Weight)
+		order = append(ogsearch.SortOrder{&ogsearch.SortField{Field: fm.Weight, Desc: true, Type: ogsearch.SortFieldAsNumber}}, order...)
+	}
+	req.SortByCustom(order)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := i.searchInContext(ctx, "suggest", req)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]search.Suggestion, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		s := search.Suggestion{
+			ID:    h.ID,
+			Text:  completionText(h.Fields[field], string(term)),
+			Score: h.Score,
+		}
+		if w, ok := h.Fields[fm.Weight].(float64); ok {
+			s.Weight = w
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, nil
+}
+
+func (i *Index) completionField(field string) (search.FieldMapping, bool) {
+	for _, f := range i.schema.Fields {
+		if f.Path == field && f.Type == search.FieldTypeCompletion {
+			return f, true
+		}
+	}
+	return search.FieldMapping{}, false
+}
+
+// completionText picks the value of a multi valued completion field that
+// starts with the prefix, falling back to the first value.
+func completionText(v interface{}, prefix string) string {
+	values, ok := v.([]interface{})
+	if !ok {
+		s, _ := v.(string)
+		return s
+	}
+
+	var first string
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if first == "" {
+			first = s
+		}
+		if strings.HasPrefix(foldASCII(strings.ToLower(s)), prefix) {
+			return s
+		}
+	}
+	return first
+}
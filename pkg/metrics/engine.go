@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jsteenb2/search"
+)
+
+// Engine records the metrics of the engine it wraps and of its indices.
+type Engine struct {
+	engine   search.Engine
+	recorder Recorder
+}
+
+var _ search.Engine = (*Engine)(nil)
+
+func NewEngine(engine search.Engine, recorder Recorder) *Engine {
+	return &Engine{
+		engine:   engine,
+		recorder: recorder,
+	}
+}
+
+func (e *Engine) Index(name string) search.Index {
+	return NewIndex(e.engine.Index(name), e.recorder)
+}
+
+func (e *Engine) LookupIndex(name string) (search.Index, error) {
+	index, err := e.engine.LookupIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(index, e.recorder), nil
+}
+
+func (e *Engine) Indices() []search.Index {
+	indices := e.engine.Indices()
+	for i, index := range indices {
+		indices[i] = NewIndex(index, e.recorder)
+	}
+	return indices
+}
+
+func (e *Engine) CreateIndex(ctx context.Context, name string, schema search.Schema) (search.Index, error) {
+	start := time.Now()
+	index, err := e.engine.CreateIndex(ctx, name, schema)
+	record(e.recorder, Labels{"index": name, "op": "create_index"}, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(index, e.recorder), nil
+}
+
+func (e *Engine) CloseIndex(ctx context.Context, name string) error {
+	start := time.Now()
+	err := e.engine.CloseIndex(ctx, name)
+	record(e.recorder, Labels{"index": name, "op": "close_index"}, start, err)
+	return err
+}
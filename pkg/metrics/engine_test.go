@@ -0,0 +1,79 @@
+package metrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jsteenb2/search"
+	"github.com/jsteenb2/search/pkg/engine/bleve"
+	"github.com/jsteenb2/search/pkg/metrics"
+	searchtest "github.com/jsteenb2/search/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Engine(t *testing.T) {
+	initFn := func(t *testing.T) (search.Engine, string, func()) {
+		engine, err := bleve.NewEngine(bleve.IndexCfg{Name: "base"})
+		require.NoError(t, err)
+
+		return metrics.NewEngine(engine, metrics.NewRegistry()), "base", func() {}
+	}
+
+	searchtest.TestSearchQueries(t, initFn)
+}
+
+func Test_EngineMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	bleveEngine, err := bleve.NewEngine(bleve.IndexCfg{Name: "products"})
+	require.NoError(t, err)
+
+	registry := metrics.NewRegistry()
+	engine := metrics.NewEngine(bleveEngine, registry)
+
+	index := engine.Index("products")
+	require.NoError(t, index.Index(ctx, "phone", map[string]interface{}{"text": "phone"}))
+	require.NoError(t, index.IndexBatch(ctx,
+		search.Document{ID: "case", Data: map[string]interface{}{"text": "phone case"}},
+	))
+
+	for i := 0; i < 3; i++ {
+		_, err := index.Search(ctx, search.NewQueryMatch("phone").SetField("text"))
+		require.NoError(t, err)
+	}
+	_, err = index.Search(ctx, search.NewQueryMatchPhrase("phone case").SetField("text"))
+	require.NoError(t, err)
+
+	_, err = engine.Index("missing").Search(ctx, search.NewQueryMatchAll())
+	require.Error(t, err)
+
+	_, err = engine.CreateIndex(ctx, "products", search.Schema{})
+	require.Error(t, err)
+
+	match := metrics.Labels{"index": "products", "op": "search", "query_type": "match"}
+	assert.Equal(t, float64(3), registry.Counter(metrics.RequestsTotal, match))
+	assert.Equal(t, float64(0), registry.Counter(metrics.ErrorsTotal, match))
+	assert.Equal(t, uint64(3), registry.HistogramCount(metrics.DurationSeconds, match))
+
+	phrase := metrics.Labels{"index": "products", "op": "search", "query_type": "match_phrase"}
+	assert.Equal(t, float64(1), registry.Counter(metrics.RequestsTotal, phrase))
+
+	for _, op := range []string{"index", "index_batch"} {
+		labels := metrics.Labels{"index": "products", "op": op}
+		assert.Equal(t, float64(1), registry.Counter(metrics.RequestsTotal, labels), op)
+		assert.Equal(t, uint64(1), registry.HistogramCount(metrics.DurationSeconds, labels), op)
+	}
+
+	missing := metrics.Labels{"index": "missing", "op": "search", "query_type": "match_all"}
+	assert.Equal(t, float64(1), registry.Counter(metrics.RequestsTotal, missing))
+	assert.Equal(t, float64(1), registry.Counter(metrics.ErrorsTotal, missing))
+
+	create := metrics.Labels{"index": "products", "op": "create_index"}
+	assert.Equal(t, float64(1), registry.Counter(metrics.ErrorsTotal, create))
+
+	indices := engine.Indices()
+	require.Len(t, indices, 1)
+	_, ok := indices[0].(*metrics.Index)
+	assert.True(t, ok)
+}
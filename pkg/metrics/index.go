@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jsteenb2/search"
+)
+
+// The metrics recorded for every operation, labeled by index, operation and,
+// for searches, query type.
+const (
+	RequestsTotal   = "search_requests_total"
+	ErrorsTotal     = "search_errors_total"
+	DurationSeconds = "search_duration_seconds"
+)
+
+// Index records the metrics of the operations of the index it wraps.
+type Index struct {
+	index    search.Index
+	recorder Recorder
+}
+
+var _ search.Index = (*Index)(nil)
+
+func NewIndex(index search.Index, recorder Recorder) *Index {
+	return &Index{
+		index:    index,
+		recorder: recorder,
+	}
+}
+
+func (i *Index) Name() string {
+	return i.index.Name()
+}
+
+func (i *Index) Index(ctx context.Context, id string, data interface{}) error {
+	start := time.Now()
+	err := i.index.Index(ctx, id, data)
+	record(i.recorder, Labels{"index": i.Name(), "op": "index"}, start, err)
+	return err
+}
+
+func (i *Index) IndexBatch(ctx context.Context, docs ...search.Document) error {
+	start := time.Now()
+	err := i.index.IndexBatch(ctx, docs...)
+	record(i.recorder, Labels{"index": i.Name(), "op": "index_batch"}, start, err)
+	return err
+}
+
+func (i *Index) Search(ctx context.Context, q search.Query, opts ...search.SearchOptFn) (*search.Result, error) {
+	start := time.Now()
+	result, err := i.index.Search(ctx, q, opts...)
+	record(i.recorder, Labels{"index": i.Name(), "op": "search", "query_type": q.QueryPlan().Type.Name()}, start, err)
+	return result, err
+}
+
+func (i *Index) Suggest(ctx context.Context, field, prefix string, opts ...search.SuggestOptFn) ([]search.Suggestion, error) {
+	start := time.Now()
+	suggestions, err := i.index.Suggest(ctx, field, prefix, opts...)
+	record(i.recorder, Labels{"index": i.Name(), "op": "suggest"}, start, err)
+	return suggestions, err
+}
+
+func (i *Index) SuggestTerms(ctx context.Context, field, text string, opts ...search.TermSuggestOptFn) ([]search.TermSuggestion, error) {
+	start := time.Now()
+	suggestions, err := i.index.SuggestTerms(ctx, field, text, opts...)
+	record(i.recorder, Labels{"index": i.Name(), "op": "suggest_terms"}, start, err)
+	return suggestions, err
+}
+
+func record(r Recorder, labels Labels, start time.Time, err error) {
+	r.Add(RequestsTotal, labels, 1)
+	if err != nil {
+		r.Add(ErrorsTotal, labels, 1)
+	}
+	r.Observe(DurationSeconds, labels, time.Since(start).Seconds())
+}
@@ -0,0 +1,51 @@
+// Package metrics instruments engines and indices with request counters and
+// latency histograms by index and query type.
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Recorder records the metrics of the instrumented engines and indices.
+type Recorder interface {
+	// Add adds the delta to the counter with the labels.
+	Add(name string, labels Labels, delta float64)
+	// Observe adds the value to the histogram with the labels.
+	Observe(name string, labels Labels, value float64)
+}
+
+// Labels are the label names and values of a series.
+type Labels map[string]string
+
+func (l Labels) copy() Labels {
+	c := make(Labels, len(l))
+	for k, v := range l {
+		c[k] = v
+	}
+	return c
+}
+
+// format renders the labels sorted by name between braces, followed by the
+// extra label name and value pairs.
+func (l Labels) format(extra ...string) string {
+	if len(l) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for _, name := range names {
+		pairs = append(pairs, name+"="+strconv.Quote(l[name]))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+"="+strconv.Quote(extra[i+1]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,238 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Registry is a Recorder keeping the metrics in memory. It exports them in
+// the Prometheus text format with WritePrometheus and as JSON with String,
+// which makes it an expvar.Var:
+//
+//	expvar.Publish("search", registry)
+type Registry struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]*counter
+	histograms map[string]*histogram
+}
+
+var _ Recorder = (*Registry)(nil)
+
+// counters and histograms are keyed by their name followed by their labels,
+// such that the series of a metric sort together.
+type counter struct {
+	name   string
+	labels Labels
+	value  float64
+}
+
+type histogram struct {
+	name   string
+	labels Labels
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type RegistryOptFn func(*Registry)
+
+// WithBuckets sets the upper bounds of the histogram buckets, in ascending
+// order. Defaults to DefaultBuckets.
+func WithBuckets(buckets ...float64) RegistryOptFn {
+	return func(r *Registry) {
+		r.buckets = buckets
+	}
+}
+
+func NewRegistry(opts ...RegistryOptFn) *Registry {
+	r := &Registry{
+		buckets:    DefaultBuckets,
+		counters:   make(map[string]*counter),
+		histograms: make(map[string]*histogram),
+	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+func (r *Registry) Add(name string, labels Labels, delta float64) {
+	key := name + labels.format()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.counters[key]
+	if !ok {
+		c = &counter{name: name, labels: labels.copy()}
+		r.counters[key] = c
+	}
+	c.value += delta
+}
+
+func (r *Registry) Observe(name string, labels Labels, value float64) {
+	key := name + labels.format()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, ok := r.histograms[key]
+	if !ok {
+		h = &histogram{
+			name:   name,
+			labels: labels.copy(),
+			counts: make([]uint64, len(r.buckets)),
+		}
+		r.histograms[key] = h
+	}
+	for i, upper := range r.buckets {
+		if value <= upper {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// Counter returns the value of the counter with the labels.
+func (r *Registry) Counter(name string, labels Labels) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.counters[name+labels.format()]; ok {
+		return c.value
+	}
+	return 0
+}
+
+// HistogramCount returns the number of values observed by the histogram
+// with the labels.
+func (r *Registry) HistogramCount(name string, labels Labels) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if h, ok := r.histograms[name+labels.format()]; ok {
+		return h.count
+	}
+	return 0
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition
+// format, sorted by name and labels.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	var last string
+	for _, key := range sortedCounterKeys(r.counters) {
+		c := r.counters[key]
+		if c.name != last {
+			fmt.Fprintf(&b, "# TYPE %s counter\n", c.name)
+			last = c.name
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", c.name, c.labels.format(), formatFloat(c.value))
+	}
+	for _, key := range sortedHistogramKeys(r.histograms) {
+		h := r.histograms[key]
+		if h.name != last {
+			fmt.Fprintf(&b, "# TYPE %s histogram\n", h.name)
+			last = h.name
+		}
+		for i, upper := range r.buckets {
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", h.name, h.labels.format("le", formatFloat(upper)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", h.name, h.labels.format("le", "+Inf"), h.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", h.name, h.labels.format(), formatFloat(h.sum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", h.name, h.labels.format(), h.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// PrometheusHandler serves the metrics in the Prometheus text exposition
+// format.
+func (r *Registry) PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		r.WritePrometheus(w)
+	})
+}
+
+type expvarHistogram struct {
+	Count   uint64            `json:"count"`
+	Sum     float64           `json:"sum"`
+	Buckets map[string]uint64 `json:"buckets"`
+}
+
+// String returns the metrics as a JSON object holding the series of each
+// metric by their labels, implementing expvar.Var.
+func (r *Registry) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vars := make(map[string]map[string]interface{})
+	series := func(name string) map[string]interface{} {
+		s, ok := vars[name]
+		if !ok {
+			s = make(map[string]interface{})
+			vars[name] = s
+		}
+		return s
+	}
+
+	for _, c := range r.counters {
+		series(c.name)[c.labels.format()] = c.value
+	}
+	for _, h := range r.histograms {
+		buckets := make(map[string]uint64, len(r.buckets))
+		for i, upper := range r.buckets {
+			buckets[formatFloat(upper)] = h.counts[i]
+		}
+		series(h.name)[h.labels.format()] = expvarHistogram{
+			Count:   h.count,
+			Sum:     h.sum,
+			Buckets: buckets,
+		}
+	}
+
+	b, err := json.Marshal(vars)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+func sortedCounterKeys(m map[string]*counter) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedHistogramKeys(m map[string]*histogram) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
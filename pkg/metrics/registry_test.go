@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/search/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry(t *testing.T) {
+	newRegistry := func() *metrics.Registry {
+		r := metrics.NewRegistry(metrics.WithBuckets(0.1, 1))
+		labels := metrics.Labels{"op": "search", "index": "products"}
+		r.Add("search_requests_total", labels, 1)
+		r.Add("search_requests_total", labels, 2)
+		r.Add("search_requests_total", metrics.Labels{"op": "index", "index": `quo"te`}, 1)
+		r.Observe("search_duration_seconds", labels, 0.05)
+		r.Observe("search_duration_seconds", labels, 0.5)
+		r.Observe("search_duration_seconds", labels, 5)
+		return r
+	}
+
+	t.Run("prometheus", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, newRegistry().WritePrometheus(&b))
+
+		expected := `# TYPE search_requests_total counter
+search_requests_total{index="products",op="search"} 3
+search_requests_total{index="quo\"te",op="index"} 1
+# TYPE search_duration_seconds histogram
+search_duration_seconds_bucket{index="products",op="search",le="0.1"} 1
+search_duration_seconds_bucket{index="products",op="search",le="1"} 2
+search_duration_seconds_bucket{index="products",op="search",le="+Inf"} 3
+search_duration_seconds_sum{index="products",op="search"} 5.55
+search_duration_seconds_count{index="products",op="search"} 3
+`
+		assert.Equal(t, expected, b.String())
+	})
+
+	t.Run("prometheus handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newRegistry().PrometheusHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+		assert.Equal(t, 200, rec.Code)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+		assert.Contains(t, rec.Body.String(), `search_requests_total{index="products",op="search"} 3`)
+	})
+
+	t.Run("expvar", func(t *testing.T) {
+		expvar.Publish("search_test", newRegistry())
+
+		var vars map[string]map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(expvar.Get("search_test").String()), &vars))
+
+		assert.JSONEq(t, "3", string(vars["search_requests_total"][`{index="products",op="search"}`]))
+		assert.JSONEq(t,
+			`{"count":3,"sum":5.55,"buckets":{"0.1":1,"1":2}}`,
+			string(vars["search_duration_seconds"][`{index="products",op="search"}`]),
+		)
+	})
+}
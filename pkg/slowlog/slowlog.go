@@ -0,0 +1,198 @@
+// Package slowlog records the searches of an index exceeding a latency
+// threshold.
+package slowlog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jsteenb2/search"
+)
+
+const defaultBufferSize = 100
+
+// Entry is a search exceeding the threshold.
+type Entry struct {
+	Time  time.Time     `json:"time"`
+	Index string        `json:"index"`
+	Query string        `json:"query"`
+	Hits  uint64        `json:"hits"`
+	Took  time.Duration `json:"took"`
+	// Error is set for the searches failing after the threshold.
+	Error string `json:"error,omitempty"`
+	// Values holds the context values of the search by the name they were
+	// registered with WithContextValue.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// Log records the slow searches of the indices it wraps. The latest
+// entries are kept in a ring buffer and, when a writer is provided, written
+// to it as JSON lines.
+type Log struct {
+	threshold   time.Duration
+	w           io.Writer
+	sampleEvery uint64
+	values      []contextValue
+
+	mu      sync.Mutex
+	slow    uint64
+	entries []Entry
+	next    int
+	full    bool
+}
+
+type contextValue struct {
+	name string
+	key  interface{}
+}
+
+type OptFn func(*Log)
+
+// WithWriter writes every recorded entry to w as a line of JSON.
+func WithWriter(w io.Writer) OptFn {
+	return func(l *Log) {
+		l.w = w
+	}
+}
+
+// WithBufferSize sets the number of entries kept in memory. Defaults to 100.
+// No entries are kept for sizes of zero or less, they are only written to
+// the writer.
+func WithBufferSize(size int) OptFn {
+	return func(l *Log) {
+		if size < 0 {
+			size = 0
+		}
+		l.entries = make([]Entry, size)
+	}
+}
+
+// WithSampling records only one of every n slow searches. Defaults to
+// recording all of them.
+func WithSampling(n uint64) OptFn {
+	return func(l *Log) {
+		l.sampleEvery = n
+	}
+}
+
+// WithContextValue records the value of the search context for the key
+// under the name.
+func WithContextValue(name string, key interface{}) OptFn {
+	return func(l *Log) {
+		l.values = append(l.values, contextValue{name: name, key: key})
+	}
+}
+
+// New creates a log of the searches taking longer than the threshold.
+func New(threshold time.Duration, opts ...OptFn) *Log {
+	l := &Log{
+		threshold:   threshold,
+		sampleEvery: 1,
+		entries:     make([]Entry, defaultBufferSize),
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// Wrap returns the index recording its slow searches in the log.
+func (l *Log) Wrap(index search.Index) search.Index {
+	return &Index{index: index, log: l}
+}
+
+// Entries returns the entries kept in memory, oldest first.
+func (l *Log) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]Entry(nil), l.entries[:l.next]...)
+	}
+	return append(append([]Entry(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+}
+
+func (l *Log) record(ctx context.Context, e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.slow++
+	if l.sampleEvery > 1 && (l.slow-1)%l.sampleEvery != 0 {
+		return
+	}
+
+	for _, v := range l.values {
+		value := ctx.Value(v.key)
+		if value == nil {
+			continue
+		}
+		if e.Values == nil {
+			e.Values = make(map[string]interface{}, len(l.values))
+		}
+		e.Values[v.name] = value
+	}
+
+	if len(l.entries) > 0 {
+		l.entries[l.next] = e
+		l.next = (l.next + 1) % len(l.entries)
+		l.full = l.full || l.next == 0
+	}
+
+	// failing to write the log must not fail the search
+	if l.w != nil {
+		_ = json.NewEncoder(l.w).Encode(e)
+	}
+}
+
+// Index records the searches exceeding the threshold of its log.
+type Index struct {
+	index search.Index
+	log   *Log
+}
+
+var _ search.Index = (*Index)(nil)
+
+func (i *Index) Name() string {
+	return i.index.Name()
+}
+
+func (i *Index) Index(ctx context.Context, id string, data interface{}) error {
+	return i.index.Index(ctx, id, data)
+}
+
+func (i *Index) IndexBatch(ctx context.Context, docs ...search.Document) error {
+	return i.index.IndexBatch(ctx, docs...)
+}
+
+func (i *Index) Suggest(ctx context.Context, field, prefix string, opts ...search.SuggestOptFn) ([]search.Suggestion, error) {
+	return i.index.Suggest(ctx, field, prefix, opts...)
+}
+
+func (i *Index) SuggestTerms(ctx context.Context, field, text string, opts ...search.TermSuggestOptFn) ([]search.TermSuggestion, error) {
+	return i.index.SuggestTerms(ctx, field, text, opts...)
+}
+
+func (i *Index) Search(ctx context.Context, q search.Query, opts ...search.SearchOptFn) (*search.Result, error) {
+	start := time.Now()
+	result, err := i.index.Search(ctx, q, opts...)
+
+	var e Entry
+	if err != nil {
+		e.Took = time.Since(start)
+		e.Error = err.Error()
+	} else {
+		e.Hits = result.Total
+		e.Took = result.Took
+	}
+	if e.Took > i.log.threshold {
+		e.Time = start
+		e.Index = i.Name()
+		e.Query = search.FormatQuery(q)
+		i.log.record(ctx, e)
+	}
+
+	return result, err
+}
@@ -0,0 +1,137 @@
+package slowlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/jsteenb2/search/pkg/engine/bleve"
+	"github.com/jsteenb2/search/pkg/slowlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey string
+
+func Test_Log(t *testing.T) {
+	query := search.NewQueryBoolean().
+		AddMust(search.NewQueryMatch("phone").SetField("text"))
+
+	t.Run("records slow searches", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slowlog.New(0, slowlog.WithWriter(&buf), slowlog.WithContextValue("user", ctxKey("user")))
+		index := log.Wrap(newIndex(t))
+
+		ctx := context.WithValue(context.Background(), ctxKey("user"), "jane")
+		result, err := index.Search(ctx, query)
+		require.NoError(t, err)
+
+		entries := log.Entries()
+		require.Len(t, entries, 1)
+
+		e := entries[0]
+		assert.Equal(t, "products", e.Index)
+		assert.Equal(t, `boolean(must=[match(field=text, matches=["phone"])])`, e.Query)
+		assert.Equal(t, uint64(2), e.Hits)
+		assert.Equal(t, result.Took, e.Took)
+		assert.Empty(t, e.Error)
+		assert.False(t, e.Time.IsZero())
+		assert.Equal(t, map[string]interface{}{"user": "jane"}, e.Values)
+
+		var logged slowlog.Entry
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &logged))
+		assert.Equal(t, e.Query, logged.Query)
+		assert.Equal(t, e.Took, logged.Took)
+		assert.Equal(t, "jane", logged.Values["user"])
+	})
+
+	t.Run("skips fast searches", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slowlog.New(time.Hour, slowlog.WithWriter(&buf))
+		index := log.Wrap(newIndex(t))
+
+		_, err := index.Search(context.Background(), query)
+		require.NoError(t, err)
+
+		assert.Empty(t, log.Entries())
+		assert.Zero(t, buf.Len())
+	})
+
+	t.Run("records failed searches", func(t *testing.T) {
+		log := slowlog.New(0)
+		index := log.Wrap(newIndex(t))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := index.Search(ctx, query)
+		require.True(t, errors.Is(err, context.Canceled))
+
+		entries := log.Entries()
+		require.Len(t, entries, 1)
+		assert.Contains(t, entries[0].Error, "context canceled")
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		log := slowlog.New(0, slowlog.WithSampling(3))
+		index := log.Wrap(newIndex(t))
+
+		for i := 0; i < 7; i++ {
+			_, err := index.Search(context.Background(), search.NewQueryMatch("phone").SetField("text"))
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, log.Entries(), 3)
+	})
+
+	t.Run("ring buffer", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slowlog.New(0, slowlog.WithBufferSize(2), slowlog.WithWriter(&buf))
+		index := log.Wrap(newIndex(t))
+
+		for _, term := range []string{"phone", "case", "laptop"} {
+			_, err := index.Search(context.Background(), search.NewQueryTerm(term).SetField("text"))
+			require.NoError(t, err)
+		}
+
+		entries := log.Entries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, `term(field=text, matches=["case"])`, entries[0].Query)
+		assert.Equal(t, `term(field=text, matches=["laptop"])`, entries[1].Query)
+
+		assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 3)
+	})
+
+	t.Run("negative buffer size", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slowlog.New(0, slowlog.WithBufferSize(-1), slowlog.WithWriter(&buf))
+		index := log.Wrap(newIndex(t))
+
+		_, err := index.Search(context.Background(), search.NewQueryTerm("phone").SetField("text"))
+		require.NoError(t, err)
+
+		assert.Empty(t, log.Entries())
+		assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 1)
+	})
+}
+
+func newIndex(t *testing.T) search.Index {
+	t.Helper()
+
+	engine, err := bleve.NewEngine(bleve.IndexCfg{Name: "products"})
+	require.NoError(t, err)
+
+	index := engine.Index("products")
+	err = index.IndexBatch(context.Background(),
+		search.Document{ID: "cheap phone", Data: map[string]interface{}{"text": "phone"}},
+		search.Document{ID: "phone case", Data: map[string]interface{}{"text": "phone case"}},
+		search.Document{ID: "laptop", Data: map[string]interface{}{"text": "laptop"}},
+	)
+	require.NoError(t, err)
+	return index
+}
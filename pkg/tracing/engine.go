@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/jsteenb2/search"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Engine creates spans for the index management of the engine it wraps and
+// the operations of its indices.
+type Engine struct {
+	engine search.Engine
+	opts   []OptFn
+	tracer trace.Tracer
+}
+
+var _ search.Engine = (*Engine)(nil)
+
+func NewEngine(engine search.Engine, opts ...OptFn) *Engine {
+	return &Engine{
+		engine: engine,
+		opts:   opts,
+		tracer: newTracer(opts),
+	}
+}
+
+func (e *Engine) Index(name string) search.Index {
+	return NewIndex(e.engine.Index(name), e.opts...)
+}
+
+func (e *Engine) LookupIndex(name string) (search.Index, error) {
+	index, err := e.engine.LookupIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(index, e.opts...), nil
+}
+
+func (e *Engine) Indices() []search.Index {
+	indices := e.engine.Indices()
+	for i, index := range indices {
+		indices[i] = NewIndex(index, e.opts...)
+	}
+	return indices
+}
+
+func (e *Engine) CreateIndex(ctx context.Context, name string, schema search.Schema) (search.Index, error) {
+	ctx, span := e.tracer.Start(ctx, "search.CreateIndex", trace.WithAttributes(IndexKey.String(name)))
+	defer span.End()
+
+	index, err := e.engine.CreateIndex(ctx, name, schema)
+	if err != nil {
+		setError(span, err)
+		return nil, err
+	}
+	return NewIndex(index, e.opts...), nil
+}
+
+func (e *Engine) CloseIndex(ctx context.Context, name string) error {
+	ctx, span := e.tracer.Start(ctx, "search.CloseIndex", trace.WithAttributes(IndexKey.String(name)))
+	defer span.End()
+
+	err := e.engine.CloseIndex(ctx, name)
+	setError(span, err)
+	return err
+}
@@ -0,0 +1,104 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jsteenb2/search"
+	"github.com/jsteenb2/search/pkg/engine/bleve"
+	"github.com/jsteenb2/search/pkg/tracing"
+	searchtest "github.com/jsteenb2/search/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_Engine(t *testing.T) {
+	initFn := func(t *testing.T) (search.Engine, string, func()) {
+		engine, err := bleve.NewEngine(bleve.IndexCfg{Name: "base"})
+		require.NoError(t, err)
+
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracetest.NewInMemoryExporter()))
+		return tracing.NewEngine(engine, tracing.WithTracerProvider(provider)), "base", func() {}
+	}
+
+	searchtest.TestSearchQueries(t, initFn)
+}
+
+func Test_EngineSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	bleveEngine, err := bleve.NewEngine(bleve.IndexCfg{Name: "products"})
+	require.NoError(t, err)
+	engine := tracing.NewEngine(bleveEngine, tracing.WithTracerProvider(provider))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	index := engine.Index("products")
+
+	require.NoError(t, index.Index(ctx, "phone", map[string]interface{}{"text": "phone"}))
+	require.NoError(t, index.IndexBatch(ctx,
+		search.Document{ID: "case", Data: map[string]interface{}{"text": "phone case"}},
+		search.Document{ID: "laptop", Data: map[string]interface{}{"text": "laptop"}},
+	))
+
+	q := search.NewQueryBoolean().
+		AddMust(search.NewQueryMatch("phone").SetField("text")).
+		AddMustNot(search.NewQueryBoosting(
+			search.NewQueryTerm("case").SetField("text"),
+			search.NewQueryMatchAll(),
+			0.5,
+		))
+	result, err := index.Search(ctx, q)
+	require.NoError(t, err)
+
+	_, err = engine.Index("missing").Search(ctx, search.NewQueryMatchAll())
+	require.Error(t, err)
+
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 5)
+	for _, s := range spans[:4] {
+		assert.Equal(t, parent.SpanContext().TraceID(), s.SpanContext.TraceID(), s.Name)
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent.SpanID(), s.Name)
+	}
+
+	indexSpan := spans[0]
+	assert.Equal(t, "search.Index", indexSpan.Name)
+	assert.Equal(t, codes.Unset, indexSpan.Status.Code)
+	assertAttribute(t, indexSpan, tracing.IndexKey.String("products"))
+
+	batchSpan := spans[1]
+	assert.Equal(t, "search.IndexBatch", batchSpan.Name)
+	assertAttribute(t, batchSpan, tracing.BatchSizeKey.Int(2))
+
+	searchSpan := spans[2]
+	assert.Equal(t, "search.Search", searchSpan.Name)
+	assertAttribute(t, searchSpan, tracing.IndexKey.String("products"))
+	assertAttribute(t, searchSpan, tracing.QueryTypeKey.String("boolean"))
+	assertAttribute(t, searchSpan, tracing.QueryDepthKey.Int(3))
+	assertAttribute(t, searchSpan, tracing.HitsKey.Int64(int64(result.Total)))
+
+	errSpan := spans[3]
+	assert.Equal(t, "search.Search", errSpan.Name)
+	assertAttribute(t, errSpan, tracing.IndexKey.String("missing"))
+	assert.Equal(t, codes.Error, errSpan.Status.Code)
+	require.Len(t, errSpan.Events, 1)
+	assert.Equal(t, "exception", errSpan.Events[0].Name)
+}
+
+func assertAttribute(t *testing.T, span tracetest.SpanStub, expected attribute.KeyValue) {
+	t.Helper()
+
+	for _, attr := range span.Attributes {
+		if attr.Key == expected.Key {
+			assert.Equal(t, expected.Value, attr.Value, string(expected.Key))
+			return
+		}
+	}
+	t.Errorf("span %s has no attribute %s", span.Name, expected.Key)
+}
@@ -0,0 +1,156 @@
+// Package tracing instruments engines and indices with OpenTelemetry spans.
+package tracing
+
+import (
+	"context"
+
+	"github.com/jsteenb2/search"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/jsteenb2/search/pkg/tracing"
+
+// The attributes set on the spans.
+const (
+	IndexKey      = attribute.Key("search.index")
+	QueryTypeKey  = attribute.Key("search.query.type")
+	QueryDepthKey = attribute.Key("search.query.depth")
+	HitsKey       = attribute.Key("search.hits")
+	BatchSizeKey  = attribute.Key("search.batch.size")
+)
+
+type OptFn func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the provider of the tracer creating the spans.
+// Defaults to the global provider.
+func WithTracerProvider(provider trace.TracerProvider) OptFn {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+func newTracer(opts []OptFn) trace.Tracer {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, o := range opts {
+		o(&c)
+	}
+	return c.provider.Tracer(instrumentationName)
+}
+
+// Index creates a span for every operation of the index it wraps, as a
+// child of the span of the context.
+type Index struct {
+	index  search.Index
+	tracer trace.Tracer
+}
+
+var _ search.Index = (*Index)(nil)
+
+func NewIndex(index search.Index, opts ...OptFn) *Index {
+	return &Index{
+		index:  index,
+		tracer: newTracer(opts),
+	}
+}
+
+func (i *Index) Name() string {
+	return i.index.Name()
+}
+
+func (i *Index) Index(ctx context.Context, id string, data interface{}) error {
+	ctx, span := i.start(ctx, "search.Index")
+	defer span.End()
+
+	err := i.index.Index(ctx, id, data)
+	setError(span, err)
+	return err
+}
+
+func (i *Index) IndexBatch(ctx context.Context, docs ...search.Document) error {
+	ctx, span := i.start(ctx, "search.IndexBatch", BatchSizeKey.Int(len(docs)))
+	defer span.End()
+
+	err := i.index.IndexBatch(ctx, docs...)
+	setError(span, err)
+	return err
+}
+
+func (i *Index) Search(ctx context.Context, q search.Query, opts ...search.SearchOptFn) (*search.Result, error) {
+	ctx, span := i.start(ctx, "search.Search",
+		QueryTypeKey.String(q.QueryPlan().Type.Name()),
+		QueryDepthKey.Int(queryDepth(q)),
+	)
+	defer span.End()
+
+	result, err := i.index.Search(ctx, q, opts...)
+	if err != nil {
+		setError(span, err)
+		return result, err
+	}
+	span.SetAttributes(HitsKey.Int64(int64(result.Total)))
+	return result, nil
+}
+
+func (i *Index) Suggest(ctx context.Context, field, prefix string, opts ...search.SuggestOptFn) ([]search.Suggestion, error) {
+	ctx, span := i.start(ctx, "search.Suggest")
+	defer span.End()
+
+	suggestions, err := i.index.Suggest(ctx, field, prefix, opts...)
+	if err != nil {
+		setError(span, err)
+		return suggestions, err
+	}
+	span.SetAttributes(HitsKey.Int(len(suggestions)))
+	return suggestions, nil
+}
+
+func (i *Index) SuggestTerms(ctx context.Context, field, text string, opts ...search.TermSuggestOptFn) ([]search.TermSuggestion, error) {
+	ctx, span := i.start(ctx, "search.SuggestTerms")
+	defer span.End()
+
+	suggestions, err := i.index.SuggestTerms(ctx, field, text, opts...)
+	setError(span, err)
+	return suggestions, err
+}
+
+func (i *Index) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{IndexKey.String(i.Name())}, attrs...)
+	return i.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func setError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// queryDepth returns the number of levels of the query tree, 1 for a leaf
+// query.
+func queryDepth(q search.Query) int {
+	if q == nil {
+		return 0
+	}
+
+	plan := q.QueryPlan()
+	children := append([]search.Query{plan.Positive, plan.Negative}, plan.Must...)
+	children = append(children, plan.Should...)
+	children = append(children, plan.MustNot...)
+	children = append(children, plan.Filter...)
+
+	var max int
+	for _, child := range children {
+		if d := queryDepth(child); d > max {
+			max = d
+		}
+	}
+	return max + 1
+}
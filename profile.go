@@ -0,0 +1,51 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Profile reports where the time of a search was spent. It is returned in
+// Result.Profile when requested with WithProfile.
+type Profile struct {
+	// Conversion is the time spent converting the query for the engine.
+	Conversion time.Duration
+	// Backend is the time spent executing the search in the engine.
+	Backend time.Duration
+	Query   *ClauseProfile
+}
+
+// ClauseProfile is the profile of a clause of the query as executed by the
+// engine, which may differ from the query built. The time and terms of a
+// clause include the ones of its children.
+type ClauseProfile struct {
+	Description string
+	Time        time.Duration
+	// DocsVisited is the number of documents matched by the clause.
+	DocsVisited uint64
+	// TermsExpanded is the number of terms read from the index, which
+	// exceeds the number of terms of the clause for prefix and fuzzy
+	// clauses.
+	TermsExpanded uint64
+	Children      []*ClauseProfile
+}
+
+func (p *Profile) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "conversion %s, backend %s\n", p.Conversion, p.Backend)
+	p.Query.render(&b, 0)
+	return b.String()
+}
+
+func (c *ClauseProfile) render(b *strings.Builder, depth int) {
+	if c == nil {
+		return
+	}
+
+	b.WriteString(strings.Repeat("  ", depth))
+	fmt.Fprintf(b, "%s: %s, %d docs, %d terms\n", c.Description, c.Time, c.DocsVisited, c.TermsExpanded)
+	for _, child := range c.Children {
+		child.render(b, depth+1)
+	}
+}
@@ -2,6 +2,8 @@ package search
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,14 @@ func (q QueryType) String() string {
 	return queryTypes[q] + " query type"
 }
 
+// Name returns the name of the query type in snake case, e.g. match_phrase.
+func (q QueryType) Name() string {
+	if int(q) >= len(queryTypes) {
+		return "unknown"
+	}
+	return strings.Replace(queryTypes[q], " ", "_", -1)
+}
+
 const (
 	QueryTypeUnknown QueryType = iota
 	QueryTypeBoolean
@@ -31,25 +41,55 @@ const (
 	QueryTypeTerm
 	QueryTypeTermRange
 	QueryTypeWildcard
+
+	// Query types are appended to keep the values of the existing ones.
+	QueryTypeGeoBoundingBox
+	QueryTypeGeoDistance
+	QueryTypeGeoPolygon
+	QueryTypeFuzzy
+	QueryTypeTerms
+	QueryTypeConjunction
+	QueryTypeDisjunction
+	QueryTypeBoosting
+	QueryTypeDisMax
+	QueryTypeMultiMatch
+	QueryTypeExists
+	QueryTypeFunctionScore
+	QueryTypeConstantScore
+	QueryTypeMoreLikeThis
 )
 
 var queryTypes = [...]string{
-	QueryTypeUnknown:      "unknown",
-	QueryTypeBoolean:      "boolean",
-	QueryTypeBoolField:    "bool field",
-	QueryTypeDateRange:    "date range",
-	QueryTypeIDs:          "ids",
-	QueryTypeMatch:        "match",
-	QueryTypeMatchAll:     "match all",
-	QueryTypeMatchNone:    "match none",
-	QueryTypeMatchPhrase:  "match phrase",
-	QueryTypeMultiPhrase:  "multi phrase",
-	QueryTypeNumericRange: "numeric range",
-	QueryTypePrefix:       "prefix",
-	QueryTypeString:       "string",
-	QueryTypeTerm:         "term",
-	QueryTypeTermRange:    "term range",
-	QueryTypeWildcard:     "wildcard",
+	QueryTypeUnknown:        "unknown",
+	QueryTypeBoolean:        "boolean",
+	QueryTypeBoolField:      "bool field",
+	QueryTypeDateRange:      "date range",
+	QueryTypeIDs:            "ids",
+	QueryTypeMatch:          "match",
+	QueryTypeMatchAll:       "match all",
+	QueryTypeMatchNone:      "match none",
+	QueryTypeMatchPhrase:    "match phrase",
+	QueryTypeMultiPhrase:    "multi phrase",
+	QueryTypeNumericRange:   "numeric range",
+	QueryTypePrefix:         "prefix",
+	QueryTypeString:         "string",
+	QueryTypeTerm:           "term",
+	QueryTypeTermRange:      "term range",
+	QueryTypeWildcard:       "wildcard",
+	QueryTypeGeoBoundingBox: "geo bounding box",
+	QueryTypeGeoDistance:    "geo distance",
+	QueryTypeGeoPolygon:     "geo polygon",
+	QueryTypeFuzzy:          "fuzzy",
+	QueryTypeTerms:          "terms",
+	QueryTypeConjunction:    "conjunction",
+	QueryTypeDisjunction:    "disjunction",
+	QueryTypeBoosting:       "boosting",
+	QueryTypeDisMax:         "dis max",
+	QueryTypeMultiMatch:     "multi match",
+	QueryTypeExists:         "exists",
+	QueryTypeFunctionScore:  "function score",
+	QueryTypeConstantScore:  "constant score",
+	QueryTypeMoreLikeThis:   "more like this",
 }
 
 type (
@@ -63,21 +103,38 @@ type (
 		Should  []Query
 		Must    []Query
 		MustNot []Query
+		Filter  []Query
 
 		Analyzer string
 		BoostVal *Boost
 		FieldVal string
 
-		Bool      bool
-		Matches   []string
-		Fuzziness int
-		Operator  QueryOperator
-		Prefix    int
-		Terms     [][]string
+		Bool           bool
+		Matches        []string
+		Fuzziness      int
+		MinShould      int
+		Operator       QueryOperator
+		Prefix         int
+		Terms          [][]string
+		Transpositions bool
 
 		Min, Max     Bound
 		InclusiveMin bool
 		InclusiveMax bool
+
+		Points   []GeoPoint
+		Distance string
+
+		Positive      Query
+		Negative      Query
+		NegativeBoost float64
+		TieBreaker    float64
+
+		Fields     []string
+		MultiMatch MultiMatchType
+
+		FunctionScore FunctionScore
+		MoreLikeThis  MoreLikeThis
 	}
 
 	QueryMultiPhrase struct {
@@ -136,6 +193,72 @@ func (q *QueryBoolField) SetField(field string) *QueryBoolField {
 	return q
 }
 
+// QueryBoosting matches the documents matching the positive query and
+// demotes, rather than excludes, those also matching the negative query by
+// multiplying their score with the negative boost.
+type QueryBoosting struct {
+	Positive      Query
+	Negative      Query
+	NegativeBoost float64
+	BoostVal      *Boost
+}
+
+func NewQueryBoosting(positive, negative Query, negativeBoost float64) *QueryBoosting {
+	return &QueryBoosting{
+		Positive:      positive,
+		Negative:      negative,
+		NegativeBoost: negativeBoost,
+	}
+}
+
+func (q *QueryBoosting) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:          QueryTypeBoosting,
+		Positive:      q.Positive,
+		Negative:      q.Negative,
+		NegativeBoost: q.NegativeBoost,
+		BoostVal:      q.BoostVal,
+	}
+}
+
+func (q *QueryBoosting) SetBoost(b float64) *QueryBoosting {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+// QueryConstantScore matches the documents matching the query with a
+// constant score equal to its boost, which defaults to 1. A nil query
+// matches all documents.
+type QueryConstantScore struct {
+	Query    Query
+	BoostVal *Boost
+}
+
+func NewQueryConstantScore(q Query) *QueryConstantScore {
+	return &QueryConstantScore{
+		Query: q,
+	}
+}
+
+func (q *QueryConstantScore) QueryPlan() QueryPlan {
+	inner := q.Query
+	if inner == nil {
+		inner = NewQueryMatchAll()
+	}
+	return QueryPlan{
+		Type:     QueryTypeConstantScore,
+		Must:     []Query{inner},
+		BoostVal: q.BoostVal,
+	}
+}
+
+func (q *QueryConstantScore) SetBoost(b float64) *QueryConstantScore {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
 type QueryDateRange struct {
 	Start          time.Time
 	End            time.Time
@@ -186,11 +309,19 @@ func (q *QueryDateRange) SetInclusiveStart(b bool) *QueryDateRange {
 	return q
 }
 
+// QueryBoolean combines queries. Documents must match all the must and
+// filter clauses and none of the must not clauses. Only the must and should
+// clauses contribute to the score, filters leave the score untouched.
 type QueryBoolean struct {
-	Should   []Query
-	Must     []Query
-	MustNot  []Query
-	BoostVal *Boost
+	Should  []Query
+	Must    []Query
+	MustNot []Query
+	Filter  []Query
+	// MinShould is the minimum number of should clauses that must match.
+	// When unset, should clauses are optional if there are must clauses and
+	// at least one is required otherwise.
+	MinShould int
+	BoostVal  *Boost
 }
 
 func NewQueryBoolean() *QueryBoolean {
@@ -199,11 +330,13 @@ func NewQueryBoolean() *QueryBoolean {
 
 func (q *QueryBoolean) QueryPlan() QueryPlan {
 	return QueryPlan{
-		Type:     QueryTypeBoolean,
-		Should:   q.Should,
-		Must:     q.Must,
-		MustNot:  q.MustNot,
-		BoostVal: q.BoostVal,
+		Type:      QueryTypeBoolean,
+		Should:    q.Should,
+		Must:      q.Must,
+		MustNot:   q.MustNot,
+		Filter:    q.Filter,
+		MinShould: q.MinShould,
+		BoostVal:  q.BoostVal,
 	}
 }
 
@@ -213,6 +346,11 @@ func (q *QueryBoolean) SetBoost(b float64) *QueryBoolean {
 	return q
 }
 
+func (q *QueryBoolean) AddFilter(filters ...Query) *QueryBoolean {
+	q.Filter = append(q.Filter, filters...)
+	return q
+}
+
 func (q *QueryBoolean) AddMust(musts ...Query) *QueryBoolean {
 	q.Must = append(q.Must, musts...)
 	return q
@@ -228,6 +366,341 @@ func (q *QueryBoolean) AddShould(shoulds ...Query) *QueryBoolean {
 	return q
 }
 
+func (q *QueryBoolean) SetMinShould(min int) *QueryBoolean {
+	q.MinShould = min
+	return q
+}
+
+// QueryConjunction matches documents matching all of the conjuncts.
+type QueryConjunction struct {
+	Conjuncts []Query
+	BoostVal  *Boost
+}
+
+func NewQueryConjunction(conjuncts ...Query) *QueryConjunction {
+	return &QueryConjunction{
+		Conjuncts: conjuncts,
+	}
+}
+
+func (q *QueryConjunction) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:     QueryTypeConjunction,
+		Must:     q.Conjuncts,
+		BoostVal: q.BoostVal,
+	}
+}
+
+func (q *QueryConjunction) AddConjuncts(conjuncts ...Query) *QueryConjunction {
+	q.Conjuncts = append(q.Conjuncts, conjuncts...)
+	return q
+}
+
+func (q *QueryConjunction) SetBoost(b float64) *QueryConjunction {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+// QueryDisjunction matches documents matching at least Min of the
+// disjuncts, or any of them when Min is unset.
+type QueryDisjunction struct {
+	Disjuncts []Query
+	Min       int
+	BoostVal  *Boost
+}
+
+func NewQueryDisjunction(disjuncts ...Query) *QueryDisjunction {
+	return &QueryDisjunction{
+		Disjuncts: disjuncts,
+	}
+}
+
+func (q *QueryDisjunction) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:      QueryTypeDisjunction,
+		Should:    q.Disjuncts,
+		MinShould: q.Min,
+		BoostVal:  q.BoostVal,
+	}
+}
+
+func (q *QueryDisjunction) AddDisjuncts(disjuncts ...Query) *QueryDisjunction {
+	q.Disjuncts = append(q.Disjuncts, disjuncts...)
+	return q
+}
+
+func (q *QueryDisjunction) SetBoost(b float64) *QueryDisjunction {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryDisjunction) SetMin(min int) *QueryDisjunction {
+	q.Min = min
+	return q
+}
+
+// QueryDisMax matches documents matching any of the disjuncts. Rather than
+// summing the scores of the matching disjuncts, the best scoring disjunct
+// wins and the scores of the others are added multiplied by the tie
+// breaker.
+type QueryDisMax struct {
+	Disjuncts  []Query
+	TieBreaker float64
+	BoostVal   *Boost
+}
+
+func NewQueryDisMax(disjuncts ...Query) *QueryDisMax {
+	return &QueryDisMax{
+		Disjuncts: disjuncts,
+	}
+}
+
+func (q *QueryDisMax) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:       QueryTypeDisMax,
+		Should:     q.Disjuncts,
+		TieBreaker: q.TieBreaker,
+		BoostVal:   q.BoostVal,
+	}
+}
+
+func (q *QueryDisMax) AddDisjuncts(disjuncts ...Query) *QueryDisMax {
+	q.Disjuncts = append(q.Disjuncts, disjuncts...)
+	return q
+}
+
+func (q *QueryDisMax) SetBoost(b float64) *QueryDisMax {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryDisMax) SetTieBreaker(tie float64) *QueryDisMax {
+	q.TieBreaker = tie
+	return q
+}
+
+// QueryExists matches the documents with at least one indexed value for the
+// field. All matches share the same score.
+type QueryExists struct {
+	FieldVal string
+	BoostVal *Boost
+}
+
+func NewQueryExists(field string) *QueryExists {
+	return &QueryExists{
+		FieldVal: field,
+	}
+}
+
+// NewQueryMissing matches the documents without any indexed value for the
+// field, the negation of QueryExists.
+func NewQueryMissing(field string) *QueryBoolean {
+	return NewQueryBoolean().
+		AddMust(NewQueryMatchAll()).
+		AddMustNot(NewQueryExists(field))
+}
+
+func (q *QueryExists) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:     QueryTypeExists,
+		FieldVal: q.FieldVal,
+		BoostVal: q.BoostVal,
+	}
+}
+
+func (q *QueryExists) SetBoost(b float64) *QueryExists {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryExists) SetField(field string) *QueryExists {
+	q.FieldVal = field
+	return q
+}
+
+// QueryFuzzy matches terms within the edit distance of the term. The first
+// Prefix characters of the term must match exactly, a prefix also limits the
+// terms engines have to compare the term to. Transpositions of two adjacent
+// characters count as a single edit when enabled.
+type QueryFuzzy struct {
+	Term           string
+	Fuzziness      int
+	Prefix         int
+	Transpositions bool
+	FieldVal       string
+	BoostVal       *Boost
+}
+
+func NewQueryFuzzy(term string) *QueryFuzzy {
+	return &QueryFuzzy{
+		Term:           term,
+		Fuzziness:      1,
+		Transpositions: true,
+	}
+}
+
+func (q *QueryFuzzy) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:           QueryTypeFuzzy,
+		Matches:        []string{q.Term},
+		Fuzziness:      q.Fuzziness,
+		Prefix:         q.Prefix,
+		Transpositions: q.Transpositions,
+		BoostVal:       q.BoostVal,
+		FieldVal:       q.FieldVal,
+	}
+}
+
+func (q *QueryFuzzy) SetBoost(b float64) *QueryFuzzy {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryFuzzy) SetField(field string) *QueryFuzzy {
+	q.FieldVal = field
+	return q
+}
+
+func (q *QueryFuzzy) SetFuzziness(fuzz int) *QueryFuzzy {
+	q.Fuzziness = fuzz
+	return q
+}
+
+func (q *QueryFuzzy) SetPrefix(prefix int) *QueryFuzzy {
+	q.Prefix = prefix
+	return q
+}
+
+func (q *QueryFuzzy) SetTranspositions(b bool) *QueryFuzzy {
+	q.Transpositions = b
+	return q
+}
+
+// GeoPoint is a location on the earth in degrees.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func NewGeoPoint(lat, lon float64) GeoPoint {
+	return GeoPoint{
+		Lat: lat,
+		Lon: lon,
+	}
+}
+
+// QueryGeoBoundingBox matches documents with a geo point within the box.
+type QueryGeoBoundingBox struct {
+	TopLeft     GeoPoint
+	BottomRight GeoPoint
+	FieldVal    string
+	BoostVal    *Boost
+}
+
+func NewQueryGeoBoundingBox(topLeft, bottomRight GeoPoint) *QueryGeoBoundingBox {
+	return &QueryGeoBoundingBox{
+		TopLeft:     topLeft,
+		BottomRight: bottomRight,
+	}
+}
+
+func (q *QueryGeoBoundingBox) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:     QueryTypeGeoBoundingBox,
+		Points:   []GeoPoint{q.TopLeft, q.BottomRight},
+		BoostVal: q.BoostVal,
+		FieldVal: q.FieldVal,
+	}
+}
+
+func (q *QueryGeoBoundingBox) SetBoost(b float64) *QueryGeoBoundingBox {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryGeoBoundingBox) SetField(field string) *QueryGeoBoundingBox {
+	q.FieldVal = field
+	return q
+}
+
+// QueryGeoDistance matches documents with a geo point within the distance
+// of the point. The distance is a number followed by a unit, i.e. 5km, 10mi
+// or 100m.
+type QueryGeoDistance struct {
+	Point    GeoPoint
+	Distance string
+	FieldVal string
+	BoostVal *Boost
+}
+
+func NewQueryGeoDistance(point GeoPoint, distance string) *QueryGeoDistance {
+	return &QueryGeoDistance{
+		Point:    point,
+		Distance: distance,
+	}
+}
+
+func (q *QueryGeoDistance) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:     QueryTypeGeoDistance,
+		Points:   []GeoPoint{q.Point},
+		Distance: q.Distance,
+		BoostVal: q.BoostVal,
+		FieldVal: q.FieldVal,
+	}
+}
+
+func (q *QueryGeoDistance) SetBoost(b float64) *QueryGeoDistance {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryGeoDistance) SetField(field string) *QueryGeoDistance {
+	q.FieldVal = field
+	return q
+}
+
+// QueryGeoPolygon matches documents with a geo point within the polygon
+// formed by the points. The polygon is closed automatically.
+type QueryGeoPolygon struct {
+	Points   []GeoPoint
+	FieldVal string
+	BoostVal *Boost
+}
+
+func NewQueryGeoPolygon(points ...GeoPoint) *QueryGeoPolygon {
+	return &QueryGeoPolygon{
+		Points: points,
+	}
+}
+
+func (q *QueryGeoPolygon) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:     QueryTypeGeoPolygon,
+		Points:   q.Points,
+		BoostVal: q.BoostVal,
+		FieldVal: q.FieldVal,
+	}
+}
+
+func (q *QueryGeoPolygon) SetBoost(b float64) *QueryGeoPolygon {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryGeoPolygon) SetField(field string) *QueryGeoPolygon {
+	q.FieldVal = field
+	return q
+}
+
 type QueryIDs struct {
 	IDs      []string
 	BoostVal *Boost
@@ -261,6 +734,9 @@ type QueryMatch struct {
 	Prefix    int
 	Fuzziness int
 	Operator  QueryOperator
+	// MinShouldPercent is the percentage of terms that must match when
+	// using the MatchQueryOperatorPercent operator.
+	MinShouldPercent int
 }
 
 func NewQueryMatch(match string) *QueryMatch {
@@ -279,6 +755,7 @@ func (q *QueryMatch) QueryPlan() QueryPlan {
 		Prefix:    q.Prefix,
 		Fuzziness: q.Fuzziness,
 		Operator:  q.Operator,
+		MinShould: q.MinShouldPercent,
 	}
 }
 
@@ -303,6 +780,19 @@ func (q *QueryMatch) SetFuzziness(fuzz int) *QueryMatch {
 	return q
 }
 
+func (q *QueryMatch) SetOperator(op QueryOperator) *QueryMatch {
+	q.Operator = op
+	return q
+}
+
+// SetMinShouldPercent requires the percentage of the terms to match, rounded
+// down to a whole number of terms. At least one term must always match.
+func (q *QueryMatch) SetMinShouldPercent(percent int) *QueryMatch {
+	q.Operator = MatchQueryOperatorPercent
+	q.MinShouldPercent = percent
+	return q
+}
+
 func (q *QueryMatch) SetPrefix(prefix int) *QueryMatch {
 	q.Prefix = prefix
 	return q
@@ -387,6 +877,220 @@ func (q *QueryMatchPhrase) SetField(field string) *QueryMatchPhrase {
 	return q
 }
 
+// QueryMoreLikeThis matches the documents similar to the liked documents
+// and texts. The most significant terms of the likes, by tf-idf, are
+// selected from the fields and searched for. The liked documents themselves
+// are excluded from the matches, and must be indexed.
+type QueryMoreLikeThis struct {
+	MoreLikeThis
+	BoostVal *Boost
+}
+
+// MoreLikeThis holds the likes and the term selection options of a
+// QueryMoreLikeThis.
+type MoreLikeThis struct {
+	IDs    []string
+	Like   []string
+	Fields []string
+	// MinTermFreq is the minimum number of times a term must occur in the
+	// likes to be selected. Defaults to 2.
+	MinTermFreq int
+	// MinDocFreq is the minimum number of documents a term must occur in to
+	// be selected. Defaults to 1.
+	MinDocFreq int
+	// MaxQueryTerms is the maximum number of terms selected. Defaults to 25.
+	MaxQueryTerms int
+	StopWords     []string
+}
+
+func NewQueryMoreLikeThis(fields ...string) *QueryMoreLikeThis {
+	return &QueryMoreLikeThis{
+		MoreLikeThis: MoreLikeThis{
+			Fields:        fields,
+			MinTermFreq:   2,
+			MinDocFreq:    1,
+			MaxQueryTerms: 25,
+		},
+	}
+}
+
+func (q *QueryMoreLikeThis) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:         QueryTypeMoreLikeThis,
+		MoreLikeThis: q.MoreLikeThis,
+		BoostVal:     q.BoostVal,
+	}
+}
+
+func (q *QueryMoreLikeThis) AddIDs(ids ...string) *QueryMoreLikeThis {
+	q.IDs = append(q.IDs, ids...)
+	return q
+}
+
+func (q *QueryMoreLikeThis) AddLike(texts ...string) *QueryMoreLikeThis {
+	q.Like = append(q.Like, texts...)
+	return q
+}
+
+func (q *QueryMoreLikeThis) AddStopWords(words ...string) *QueryMoreLikeThis {
+	q.StopWords = append(q.StopWords, words...)
+	return q
+}
+
+func (q *QueryMoreLikeThis) SetBoost(b float64) *QueryMoreLikeThis {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryMoreLikeThis) SetMaxQueryTerms(max int) *QueryMoreLikeThis {
+	q.MaxQueryTerms = max
+	return q
+}
+
+func (q *QueryMoreLikeThis) SetMinDocFreq(min int) *QueryMoreLikeThis {
+	q.MinDocFreq = min
+	return q
+}
+
+func (q *QueryMoreLikeThis) SetMinTermFreq(min int) *QueryMoreLikeThis {
+	q.MinTermFreq = min
+	return q
+}
+
+// QueryMultiMatch matches the text against several fields. Fields may carry
+// a boost using the field^boost notation, i.e. "title^3". How the per field
+// matches are combined is determined by the MultiMatchType.
+type QueryMultiMatch struct {
+	Match      string
+	Fields     []string
+	Type       MultiMatchType
+	TieBreaker float64
+	Analyzer   string
+	Operator   QueryOperator
+	Fuzziness  int
+	BoostVal   *Boost
+	// MinShouldPercent is the percentage of terms that must match when
+	// using the MatchQueryOperatorPercent operator.
+	MinShouldPercent int
+}
+
+func NewQueryMultiMatch(match string, fields ...string) *QueryMultiMatch {
+	return &QueryMultiMatch{
+		Match:  match,
+		Fields: fields,
+	}
+}
+
+func (q *QueryMultiMatch) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:       QueryTypeMultiMatch,
+		Matches:    []string{q.Match},
+		Fields:     q.Fields,
+		MultiMatch: q.Type,
+		TieBreaker: q.TieBreaker,
+		Analyzer:   q.Analyzer,
+		Operator:   q.Operator,
+		Fuzziness:  q.Fuzziness,
+		BoostVal:   q.BoostVal,
+		MinShould:  q.MinShouldPercent,
+	}
+}
+
+func (q *QueryMultiMatch) AddFields(fields ...string) *QueryMultiMatch {
+	q.Fields = append(q.Fields, fields...)
+	return q
+}
+
+func (q *QueryMultiMatch) SetAnalyzer(analyzer string) *QueryMultiMatch {
+	q.Analyzer = analyzer
+	return q
+}
+
+func (q *QueryMultiMatch) SetBoost(b float64) *QueryMultiMatch {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryMultiMatch) SetFuzziness(fuzz int) *QueryMultiMatch {
+	q.Fuzziness = fuzz
+	return q
+}
+
+func (q *QueryMultiMatch) SetOperator(op QueryOperator) *QueryMultiMatch {
+	q.Operator = op
+	return q
+}
+
+// SetMinShouldPercent requires the percentage of the terms to match, rounded
+// down to a whole number of terms. At least one term must always match. The
+// terms must match within a single field, except for cross fields queries.
+func (q *QueryMultiMatch) SetMinShouldPercent(percent int) *QueryMultiMatch {
+	q.Operator = MatchQueryOperatorPercent
+	q.MinShouldPercent = percent
+	return q
+}
+
+func (q *QueryMultiMatch) SetTieBreaker(tie float64) *QueryMultiMatch {
+	q.TieBreaker = tie
+	return q
+}
+
+func (q *QueryMultiMatch) SetType(typ MultiMatchType) *QueryMultiMatch {
+	q.Type = typ
+	return q
+}
+
+type MultiMatchType int
+
+func (m MultiMatchType) String() string {
+	if int(m) >= len(multiMatchTypes) {
+		return "unknown multi match type"
+	}
+	return multiMatchTypes[m] + " multi match type"
+}
+
+const (
+	// MultiMatchBestFields scores documents by the best matching field, the
+	// other fields contribute their score times the tie breaker.
+	MultiMatchBestFields MultiMatchType = iota
+	// MultiMatchMostFields sums the scores of all matching fields.
+	MultiMatchMostFields
+	// MultiMatchCrossFields treats the fields as one big field, each term
+	// is scored by the best field it occurs in.
+	MultiMatchCrossFields
+	// MultiMatchPhrase matches the text as a phrase in each field and scores
+	// documents by the best matching field.
+	MultiMatchPhrase
+	// MultiMatchPhrasePrefix is MultiMatchPhrase with the last term of the
+	// text matched as a prefix.
+	MultiMatchPhrasePrefix
+)
+
+var multiMatchTypes = [...]string{
+	MultiMatchBestFields:   "best fields",
+	MultiMatchMostFields:   "most fields",
+	MultiMatchCrossFields:  "cross fields",
+	MultiMatchPhrase:       "phrase",
+	MultiMatchPhrasePrefix: "phrase prefix",
+}
+
+// ParseFieldBoost splits a field in the field^boost notation into the field
+// and its boost. Fields without a boost have a boost of 1.
+func ParseFieldBoost(field string) (string, float64, error) {
+	idx := strings.LastIndex(field, "^")
+	if idx < 0 {
+		return field, 1, nil
+	}
+
+	boost, err := strconv.ParseFloat(field[idx+1:], 64)
+	if err != nil || boost < 0 {
+		return "", 0, fmt.Errorf("invalid boost for field %q", field)
+	}
+	return field[:idx], boost, nil
+}
+
 type QueryNumericRange struct {
 	Min          NullFloat64
 	Max          NullFloat64
@@ -565,6 +1269,47 @@ func (q *QueryTermRange) SetInclusiveMax(b bool) *QueryTermRange {
 	return q
 }
 
+// QueryTerms matches documents containing any of the exact terms. When
+// MinShould is set, at least that many of the terms must match.
+type QueryTerms struct {
+	Terms     []string
+	MinShould int
+	FieldVal  string
+	BoostVal  *Boost
+}
+
+func NewQueryTerms(terms ...string) *QueryTerms {
+	return &QueryTerms{
+		Terms: terms,
+	}
+}
+
+func (q *QueryTerms) QueryPlan() QueryPlan {
+	return QueryPlan{
+		Type:      QueryTypeTerms,
+		Matches:   q.Terms,
+		MinShould: q.MinShould,
+		BoostVal:  q.BoostVal,
+		FieldVal:  q.FieldVal,
+	}
+}
+
+func (q *QueryTerms) SetBoost(b float64) *QueryTerms {
+	boost := Boost(b)
+	q.BoostVal = &boost
+	return q
+}
+
+func (q *QueryTerms) SetField(field string) *QueryTerms {
+	q.FieldVal = field
+	return q
+}
+
+func (q *QueryTerms) SetMinShould(min int) *QueryTerms {
+	q.MinShould = min
+	return q
+}
+
 type Boost float64
 
 func (b *Boost) Value() float64 {
@@ -588,6 +1333,8 @@ const (
 	MatchQueryOperatorOr = 0
 	// Document must satisfy ALL of term searches.
 	MatchQueryOperatorAnd = 1
+	// Document must satisfy a PERCENTAGE of term searches.
+	MatchQueryOperatorPercent = 2
 )
 
 type Bound interface{}
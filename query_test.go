@@ -0,0 +1,13 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryType_Name(t *testing.T) {
+	assert.Equal(t, "match_phrase", QueryTypeMatchPhrase.Name())
+	assert.Equal(t, "term", NewQueryTerm("phone").QueryPlan().Type.Name())
+	assert.Equal(t, "unknown", QueryType(1000).Name())
+}
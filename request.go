@@ -0,0 +1,197 @@
+package search
+
+const defaultSearchSize = 10
+
+// SearchRequest holds the options of a search. Requests are built from
+// SearchOptFns by NewSearchRequest.
+type SearchRequest struct {
+	Size   int
+	From   int
+	Sort   []Sort
+	Fields []string
+
+	// Explain enables the score explanation of the hits.
+	Explain bool
+	// Profile enables the profile of the search.
+	Profile bool
+
+	// DidYouMeanBelow enables the did you mean suggestion for results with a
+	// Total lower than it.
+	DidYouMeanBelow uint64
+
+	Collapse *Collapse
+
+	// After holds the sort values of the hit the results start after. It
+	// requires a sort ending with a unique tiebreaker such as SortByID.
+	After []string
+}
+
+type SearchOptFn func(*SearchRequest)
+
+func NewSearchRequest(opts ...SearchOptFn) SearchRequest {
+	req := SearchRequest{
+		Size: defaultSearchSize,
+	}
+	for _, o := range opts {
+		o(&req)
+	}
+	return req
+}
+
+// WithSize sets the maximum number of hits returned. Defaults to 10.
+func WithSize(size int) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Size = size
+	}
+}
+
+// WithFrom sets the number of hits to skip.
+func WithFrom(from int) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.From = from
+	}
+}
+
+// WithFields sets the document fields whose values are returned with each hit.
+func WithFields(fields ...string) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Fields = append(r.Fields, fields...)
+	}
+}
+
+// WithExplain returns the explanation of the score of each hit in
+// Hit.Explanation.
+func WithExplain() SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Explain = true
+	}
+}
+
+// WithProfile returns the profile of the search in Result.Profile.
+func WithProfile() SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Profile = true
+	}
+}
+
+// WithSort sets the order of the hits. Hits are sorted by score when no
+// sort is provided.
+func WithSort(sorts ...Sort) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Sort = append(r.Sort, sorts...)
+	}
+}
+
+// WithSearchAfter returns the hits sorted after the hit with the sort
+// values. Use a Cursor to iterate over all the hits of a query.
+func WithSearchAfter(values ...string) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.After = values
+	}
+}
+
+// WithDidYouMean suggests a corrected query text in Result.DidYouMean when
+// the search finds fewer hits than below. The text of the match and match
+// phrase queries is corrected.
+func WithDidYouMean(below uint64) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.DidYouMeanBelow = below
+	}
+}
+
+// WithCollapse groups the hits by the value of a field. Size and From page
+// through the groups rather than the hits. Engines may limit the number
+// of matches a collapsed search groups.
+func WithCollapse(c Collapse) SearchOptFn {
+	return func(r *SearchRequest) {
+		r.Collapse = &c
+	}
+}
+
+// Collapse returns the top hit of every group of hits sharing the value of
+// the field. Hits without a value for the field share a group with an empty
+// key.
+type Collapse struct {
+	Field string
+	// InnerHits is the number of hits of the group returned in
+	// HitGroup.InnerHits, following the top hit in the sort order.
+	InnerHits int
+}
+
+func CollapseByField(field string) Collapse {
+	return Collapse{Field: field}
+}
+
+func (c Collapse) SetInnerHits(size int) Collapse {
+	c.InnerHits = size
+	return c
+}
+
+type SortType int
+
+func (s SortType) String() string {
+	if int(s) >= len(sortTypes) {
+		return "unknown sort type"
+	}
+	return sortTypes[s] + " sort type"
+}
+
+const (
+	SortTypeScore SortType = iota
+	SortTypeField
+	SortTypeGeoDistance
+	SortTypeID
+)
+
+var sortTypes = [...]string{
+	SortTypeScore:       "score",
+	SortTypeField:       "field",
+	SortTypeGeoDistance: "geo distance",
+	SortTypeID:          "id",
+}
+
+// Sort is a single sort criteria. The sort values of each hit are
+// available in Hit.Sort, in the order the sorts were provided.
+type Sort struct {
+	Type  SortType
+	Field string
+	Desc  bool
+
+	// Point and Unit are used for geo distance sorts. The sort value is the
+	// distance between the point and the document in the unit, which
+	// defaults to meters.
+	Point GeoPoint
+	Unit  string
+}
+
+func SortByScore() Sort {
+	return Sort{
+		Type: SortTypeScore,
+		Desc: true,
+	}
+}
+
+func SortByField(field string) Sort {
+	return Sort{
+		Type:  SortTypeField,
+		Field: field,
+	}
+}
+
+func SortByGeoDistance(field string, point GeoPoint, unit string) Sort {
+	return Sort{
+		Type:  SortTypeGeoDistance,
+		Field: field,
+		Point: point,
+		Unit:  unit,
+	}
+}
+
+func SortByID() Sort {
+	return Sort{Type: SortTypeID}
+}
+
+func (s Sort) SetDesc(desc bool) Sort {
+	s.Desc = desc
+	return s
+}
@@ -0,0 +1,134 @@
+package search
+
+const defaultSuggestSize = 5
+
+// SuggestRequest holds the options of a completion suggestion. Requests are
+// built from SuggestOptFns by NewSuggestRequest.
+type SuggestRequest struct {
+	Size      int
+	Fuzziness int
+	Contexts  []SuggestContext
+}
+
+// SuggestContext restricts the suggestions to the documents with one of the
+// values in the field.
+type SuggestContext struct {
+	Field  string
+	Values []string
+}
+
+type SuggestOptFn func(*SuggestRequest)
+
+func NewSuggestRequest(opts ...SuggestOptFn) SuggestRequest {
+	req := SuggestRequest{
+		Size: defaultSuggestSize,
+	}
+	for _, o := range opts {
+		o(&req)
+	}
+	return req
+}
+
+// WithSuggestSize sets the maximum number of suggestions. Defaults to 5.
+func WithSuggestSize(size int) SuggestOptFn {
+	return func(r *SuggestRequest) {
+		r.Size = size
+	}
+}
+
+// WithSuggestFuzziness sets the number of edits tolerated in the prefix.
+func WithSuggestFuzziness(fuzz int) SuggestOptFn {
+	return func(r *SuggestRequest) {
+		r.Fuzziness = fuzz
+	}
+}
+
+// WithSuggestContext only suggests documents with one of the values in the
+// field. Multiple contexts must all match.
+func WithSuggestContext(field string, values ...string) SuggestOptFn {
+	return func(r *SuggestRequest) {
+		r.Contexts = append(r.Contexts, SuggestContext{
+			Field:  field,
+			Values: values,
+		})
+	}
+}
+
+// Suggestion is a completion of the prefix, taken from the completion field
+// of the document. Suggestions are ranked by weight and then by score.
+type Suggestion struct {
+	ID     string
+	Text   string
+	Weight float64
+	Score  float64
+}
+
+const (
+	defaultTermSuggestSize     = 3
+	defaultTermSuggestMaxEdits = 2
+	defaultTermSuggestPrefix   = 1
+)
+
+// TermSuggestRequest holds the options of a term suggestion. Requests are
+// built from TermSuggestOptFns by NewTermSuggestRequest.
+type TermSuggestRequest struct {
+	// Size is the maximum number of corrections per term. Defaults to 3.
+	Size int
+	// MaxEdits is the maximum edit distance of a correction. Defaults to 2.
+	MaxEdits int
+	// Prefix is the number of leading characters a correction must share
+	// with the term. Defaults to 1.
+	Prefix int
+}
+
+type TermSuggestOptFn func(*TermSuggestRequest)
+
+func NewTermSuggestRequest(opts ...TermSuggestOptFn) TermSuggestRequest {
+	req := TermSuggestRequest{
+		Size:     defaultTermSuggestSize,
+		MaxEdits: defaultTermSuggestMaxEdits,
+		Prefix:   defaultTermSuggestPrefix,
+	}
+	for _, o := range opts {
+		o(&req)
+	}
+	return req
+}
+
+func WithTermSuggestSize(size int) TermSuggestOptFn {
+	return func(r *TermSuggestRequest) {
+		r.Size = size
+	}
+}
+
+func WithTermSuggestMaxEdits(edits int) TermSuggestOptFn {
+	return func(r *TermSuggestRequest) {
+		r.MaxEdits = edits
+	}
+}
+
+func WithTermSuggestPrefix(prefix int) TermSuggestOptFn {
+	return func(r *TermSuggestRequest) {
+		r.Prefix = prefix
+	}
+}
+
+// TermSuggestion holds the corrections of an analyzed term of the text that
+// is not in the index. Start and End are the byte offsets of the term in
+// the text.
+type TermSuggestion struct {
+	Term        string
+	Start       int
+	End         int
+	Corrections []TermCorrection
+}
+
+// TermCorrection is a term of the index within the edit distance of the
+// misspelled term. Corrections are ranked by score, which decreases with the
+// edit distance, and then by document frequency.
+type TermCorrection struct {
+	Term     string
+	Distance int
+	DocFreq  uint64
+	Score    float64
+}
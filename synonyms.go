@@ -0,0 +1,354 @@
+package search
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// maxSynonymVariants caps the number of alternatives a single match query is
+// expanded into.
+const maxSynonymVariants = 64
+
+// Synonyms is a dictionary of synonym rules. Rules are either equivalent
+// sets, where every term is a synonym of every other term, or one way
+// mappings, where the inputs are replaced by the outputs. Terms may span
+// multiple words. All terms are lowercased.
+type Synonyms struct {
+	rules []SynonymRule
+
+	// entries is keyed by the first word of each input.
+	entries map[string][]synonymEntry
+}
+
+type SynonymRule struct {
+	Inputs []string
+	// Outputs are empty for equivalent sets.
+	Outputs []string
+}
+
+// String returns the rule in the solr synonyms format.
+func (r SynonymRule) String() string {
+	if len(r.Outputs) == 0 {
+		return strings.Join(r.Inputs, ", ")
+	}
+	return strings.Join(r.Inputs, ", ") + " => " + strings.Join(r.Outputs, ", ")
+}
+
+type synonymEntry struct {
+	input   []string
+	outputs [][]string
+}
+
+func NewSynonyms() *Synonyms {
+	return &Synonyms{
+		entries: make(map[string][]synonymEntry),
+	}
+}
+
+// NewSynonymsMap creates one way mappings from each key to its values.
+func NewSynonymsMap(m map[string][]string) *Synonyms {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	s := NewSynonyms()
+	for _, k := range keys {
+		s.AddOneWay([]string{k}, m[k]...)
+	}
+	return s
+}
+
+// ParseSynonyms reads synonyms in the solr format. Each line is either an
+// equivalent set, i.e. "tv, television", or a one way mapping, i.e.
+// "i-pod, i pod => ipod". Blank lines and lines starting with # are ignored.
+func ParseSynonyms(r io.Reader) (*Synonyms, error) {
+	s := NewSynonyms()
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if err := s.AddRule(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// AddRule adds a single rule in the solr format.
+func (s *Synonyms) AddRule(rule string) error {
+	line := strings.TrimSpace(rule)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	parts := strings.Split(line, "=>")
+	switch len(parts) {
+	case 1:
+		terms := splitSynonymTerms(parts[0])
+		if len(terms) == 0 {
+			return fmt.Errorf("invalid synonym rule %q", rule)
+		}
+		s.AddEquivalent(terms...)
+	case 2:
+		inputs, outputs := splitSynonymTerms(parts[0]), splitSynonymTerms(parts[1])
+		if len(inputs) == 0 || len(outputs) == 0 {
+			return fmt.Errorf("invalid synonym rule %q", rule)
+		}
+		s.AddOneWay(inputs, outputs...)
+	default:
+		return fmt.Errorf("invalid synonym rule %q", rule)
+	}
+	return nil
+}
+
+// AddEquivalent adds a set of terms that are all synonyms of one another.
+func (s *Synonyms) AddEquivalent(terms ...string) *Synonyms {
+	terms = normalizeSynonymTerms(terms)
+	if len(terms) == 0 {
+		return s
+	}
+
+	s.rules = append(s.rules, SynonymRule{Inputs: terms})
+	for _, t := range terms {
+		s.addEntry(t, terms)
+	}
+	return s
+}
+
+// AddOneWay adds a mapping that replaces any of the inputs with the outputs.
+// To keep the input, include it in the outputs.
+func (s *Synonyms) AddOneWay(inputs []string, outputs ...string) *Synonyms {
+	inputs, outputs = normalizeSynonymTerms(inputs), normalizeSynonymTerms(outputs)
+	if len(inputs) == 0 || len(outputs) == 0 {
+		return s
+	}
+
+	s.rules = append(s.rules, SynonymRule{
+		Inputs:  inputs,
+		Outputs: outputs,
+	})
+	for _, in := range inputs {
+		s.addEntry(in, outputs)
+	}
+	return s
+}
+
+func (s *Synonyms) addEntry(input string, outputs []string) {
+	words := strings.Fields(input)
+	if s.entries == nil {
+		s.entries = make(map[string][]synonymEntry)
+	}
+
+	entries := s.entries[words[0]]
+	idx := -1
+	for i, e := range entries {
+		if strings.Join(e.input, " ") == input {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		entries = append(entries, synonymEntry{input: words})
+		idx = len(entries) - 1
+	}
+
+	for _, out := range outputs {
+		outWords := strings.Fields(out)
+		if !containsWords(entries[idx].outputs, outWords) {
+			entries[idx].outputs = append(entries[idx].outputs, outWords)
+		}
+	}
+	s.entries[words[0]] = entries
+}
+
+// Rules returns the rules of the dictionary in the order they were added.
+func (s *Synonyms) Rules() []SynonymRule {
+	if s == nil {
+		return nil
+	}
+	return s.rules
+}
+
+// Lookup finds the longest input starting at the beginning of words. It
+// returns the number of words matched and the words of every replacement.
+func (s *Synonyms) Lookup(words []string) (int, [][]string) {
+	if s == nil || len(words) == 0 {
+		return 0, nil
+	}
+
+	var (
+		matched int
+		outputs [][]string
+	)
+	for _, e := range s.entries[words[0]] {
+		if len(e.input) <= matched || len(e.input) > len(words) {
+			continue
+		}
+		if !equalWords(e.input, words[:len(e.input)]) {
+			continue
+		}
+		matched, outputs = len(e.input), e.outputs
+	}
+	return matched, outputs
+}
+
+// Expand returns every variant of the text with the synonyms applied. The
+// original text is included only when a rule keeps it.
+func (s *Synonyms) Expand(text string) []string {
+	words := strings.Fields(strings.ToLower(text))
+
+	variants := [][]string{nil}
+	var expanded bool
+	for i := 0; i < len(words); {
+		n, outputs := s.Lookup(words[i:])
+		if n == 0 {
+			for j := range variants {
+				variants[j] = append(variants[j], words[i])
+			}
+			i++
+			continue
+		}
+
+		expanded = true
+		next := make([][]string, 0, len(variants)*len(outputs))
+		for _, v := range variants {
+			for _, out := range outputs {
+				if len(next) == maxSynonymVariants {
+					break
+				}
+				variant := append(append([]string{}, v...), out...)
+				next = append(next, variant)
+			}
+		}
+		variants = next
+		i += n
+	}
+
+	if !expanded {
+		return []string{text}
+	}
+
+	out := make([]string, 0, len(variants))
+	for _, v := range variants {
+		out = append(out, strings.Join(v, " "))
+	}
+	return out
+}
+
+// ExpandSynonyms rewrites the match and match phrase queries within q into
+// a boolean query of the alternatives produced by the synonyms. Boolean
+// and constant score queries are rewritten recursively, all other queries
+// are returned as is.
+func ExpandSynonyms(q Query, syn *Synonyms) Query {
+	switch q := q.(type) {
+	case *QueryBoolean:
+		expanded := *q
+		expanded.Must = expandSynonymsAll(q.Must, syn)
+		expanded.Should = expandSynonymsAll(q.Should, syn)
+		expanded.MustNot = expandSynonymsAll(q.MustNot, syn)
+		expanded.Filter = expandSynonymsAll(q.Filter, syn)
+		return &expanded
+	case *QueryConstantScore:
+		if q.Query == nil {
+			return q
+		}
+		expanded := *q
+		expanded.Query = ExpandSynonyms(q.Query, syn)
+		return &expanded
+	case *QueryMatch:
+		variants := syn.Expand(q.Match)
+		if len(variants) == 1 && variants[0] == q.Match {
+			return q
+		}
+
+		alternatives := make([]Query, 0, len(variants))
+		for _, v := range variants {
+			alt := *q
+			alt.Match, alt.BoostVal = v, nil
+			alternatives = append(alternatives, &alt)
+		}
+		return synonymAlternatives(alternatives, q.BoostVal)
+	case *QueryMatchPhrase:
+		variants := syn.Expand(q.MatchPhrase)
+		if len(variants) == 1 && variants[0] == q.MatchPhrase {
+			return q
+		}
+
+		alternatives := make([]Query, 0, len(variants))
+		for _, v := range variants {
+			alt := *q
+			alt.MatchPhrase, alt.BoostVal = v, nil
+			alternatives = append(alternatives, &alt)
+		}
+		return synonymAlternatives(alternatives, q.BoostVal)
+	default:
+		return q
+	}
+}
+
+func expandSynonymsAll(queries []Query, syn *Synonyms) []Query {
+	if len(queries) == 0 {
+		return queries
+	}
+
+	out := make([]Query, 0, len(queries))
+	for _, q := range queries {
+		out = append(out, ExpandSynonyms(q, syn))
+	}
+	return out
+}
+
+func synonymAlternatives(alternatives []Query, boost *Boost) Query {
+	q := NewQueryBoolean().AddShould(alternatives...)
+	q.BoostVal = boost
+	return q
+}
+
+func splitSynonymTerms(s string) []string {
+	var terms []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+func normalizeSynonymTerms(terms []string) []string {
+	out := make([]string, 0, len(terms))
+	for _, t := range terms {
+		t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+		if t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func containsWords(list [][]string, words []string) bool {
+	for _, l := range list {
+		if equalWords(l, words) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,581 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemas(t *testing.T, engineInitFn SchemaInitFn) {
+	schemaTests := []struct {
+		name   string
+		testFn func(t *testing.T, engineInitFn SchemaInitFn)
+	}{
+		{
+			name:   "analyzers",
+			testFn: TestAnalyzers,
+		},
+		{
+			name:   "synonyms",
+			testFn: TestSynonyms,
+		},
+		{
+			name:   "languages",
+			testFn: TestLanguages,
+		},
+		{
+			name:   "geo",
+			testFn: TestQueryGeo,
+		},
+		{
+			name:   "suggest",
+			testFn: TestSuggest,
+		},
+	}
+
+	for _, tt := range schemaTests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.testFn(t, engineInitFn)
+		})
+	}
+}
+
+func TestAnalyzers(t *testing.T, engineInitFn SchemaInitFn) {
+	t.Helper()
+
+	schema := search.Schema{
+		Analyzers: []search.Analyzer{
+			*search.
+				NewAnalyzer("stemmed", search.NewTokenizerUnicode()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterStopLanguage(search.LanguageEnglish),
+					search.NewTokenFilterStemmer(search.LanguageEnglish),
+				),
+			*search.
+				NewAnalyzer("folded", search.NewTokenizerUnicode()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterASCIIFolding(),
+				),
+			*search.
+				NewAnalyzer("prefixes", search.NewTokenizerUnicode()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterEdgeNgram(2, 10),
+				),
+			*search.
+				NewAnalyzer("grams", search.NewTokenizerKeyword()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterNgram(3, 3),
+				),
+			*search.
+				NewAnalyzer("stripped", search.NewTokenizerWhitespace()).
+				AddCharFilters(search.NewCharFilterHTML()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterStop("ignored"),
+				),
+		},
+		Fields: []search.FieldMapping{
+			*search.NewFieldMapping("title", search.FieldTypeText).SetAnalyzer("stemmed"),
+			*search.NewFieldMapping("name", search.FieldTypeText).SetAnalyzer("folded"),
+			*search.NewFieldMapping("nest.first", search.FieldTypeText).SetAnalyzer("prefixes"),
+			*search.NewFieldMapping("code", search.FieldTypeText).SetAnalyzer("grams"),
+			*search.NewFieldMapping("body", search.FieldTypeText).SetAnalyzer("stripped"),
+		},
+	}
+
+	engine, indexName, cleanup := engineInitFn(t, schema)
+	defer cleanup()
+
+	docs := []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "stem",
+			v:  map[string]interface{}{"title": "The Running Shoes"},
+		},
+		{
+			id: "fold",
+			v:  map[string]interface{}{"name": "Crème Brûlée"},
+		},
+		{
+			id: "edge",
+			v: map[string]interface{}{
+				"nest": map[string]interface{}{
+					"first": "Shoelaces",
+				},
+			},
+		},
+		{
+			id: "gram",
+			v:  map[string]interface{}{"code": "XJ-2000"},
+		},
+		{
+			id: "html",
+			v:  map[string]interface{}{"body": "<b>Bold</b> ignored words"},
+		},
+	}
+
+	seedIndex(t, engine, indexName, docs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "stemmed match",
+			query: search.
+				NewQueryMatch("runs").
+				SetField("title"),
+			expected: []string{"stem"},
+		},
+		{
+			name: "stemmed stop word",
+			query: search.
+				NewQueryMatch("the").
+				SetField("title"),
+			expected: []string{},
+		},
+		{
+			name: "keyword analyzer skips stemming",
+			query: search.
+				NewQueryMatch("running").
+				SetField("title").
+				SetAnalyzer("keyword"),
+			expected: []string{},
+		},
+		{
+			name: "folded match",
+			query: search.
+				NewQueryMatch("creme brulee").
+				SetField("name").
+				SetOperator(search.MatchQueryOperatorAnd),
+			expected: []string{"fold"},
+		},
+		{
+			name: "folded match with diacritics",
+			query: search.
+				NewQueryMatch("CRÈME").
+				SetField("name"),
+			expected: []string{"fold"},
+		},
+		{
+			name: "custom analyzer on query",
+			query: search.
+				NewQueryMatchPhrase("Crème Brûlée").
+				SetField("name").
+				SetAnalyzer("folded"),
+			expected: []string{"fold"},
+		},
+		{
+			name: "nested edge ngram",
+			query: search.
+				NewQueryTerm("sho").
+				SetField("nest.first"),
+			expected: []string{"edge"},
+		},
+		{
+			name: "nested edge ngram is anchored",
+			query: search.
+				NewQueryTerm("lace").
+				SetField("nest.first"),
+			expected: []string{},
+		},
+		{
+			name: "ngram",
+			query: search.
+				NewQueryTerm("j-2").
+				SetField("code"),
+			expected: []string{"gram"},
+		},
+		{
+			name: "html stripped",
+			query: search.
+				NewQueryMatch("bold").
+				SetField("body"),
+			expected: []string{"html"},
+		},
+		{
+			name: "html tags are not indexed",
+			query: search.
+				NewQueryTerm("b").
+				SetField("body"),
+			expected: []string{},
+		},
+		{
+			name: "custom stop word",
+			query: search.
+				NewQueryTerm("ignored").
+				SetField("body"),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestSynonyms(t *testing.T, engineInitFn SchemaInitFn) {
+	t.Helper()
+
+	synonyms, err := search.ParseSynonyms(strings.NewReader(`
+# equivalent sets
+tv, television
+
+# one way mappings
+laptop, notebook => laptop
+i pod => ipod
+big apple => new york
+nyc => new york city
+`))
+	require.NoError(t, err)
+
+	schema := search.Schema{
+		Analyzers: []search.Analyzer{
+			*search.
+				NewAnalyzer("synonyms", search.NewTokenizerUnicode()).
+				AddTokenFilters(
+					search.NewTokenFilterLowercase(),
+					search.NewTokenFilterSynonyms(synonyms),
+				),
+		},
+		Fields: []search.FieldMapping{
+			*search.NewFieldMapping("product", search.FieldTypeText).SetAnalyzer("synonyms"),
+		},
+	}
+
+	engine, indexName, cleanup := engineInitFn(t, schema)
+	defer cleanup()
+
+	docs := []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "tv",
+			v:  map[string]interface{}{"product": "Samsung Television"},
+		},
+		{
+			id: "notebook",
+			v:  map[string]interface{}{"product": "Dell Notebook"},
+		},
+		{
+			id: "ipod",
+			v:  map[string]interface{}{"product": "Apple iPod"},
+		},
+		{
+			id: "tour",
+			v:  map[string]interface{}{"product": "NYC Tour Guide"},
+		},
+		{
+			id: "stand",
+			v:  map[string]interface{}{"desc": "television stand"},
+		},
+		{
+			id: "pizza",
+			v:  map[string]interface{}{"desc": "new york pizza"},
+		},
+	}
+
+	seedIndex(t, engine, indexName, docs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "index time equivalent",
+			query: search.
+				NewQueryMatch("TV").
+				SetField("product"),
+			expected: []string{"tv"},
+		},
+		{
+			name: "index time one way",
+			query: search.
+				NewQueryMatch("laptop").
+				SetField("product"),
+			expected: []string{"notebook"},
+		},
+		{
+			name: "index time one way replaces input",
+			query: search.
+				NewQueryTerm("notebook").
+				SetField("product"),
+			expected: []string{},
+		},
+		{
+			name: "index time multi word",
+			query: search.
+				NewQueryMatch("i pod").
+				SetField("product").
+				SetOperator(search.MatchQueryOperatorAnd),
+			expected: []string{"ipod"},
+		},
+		{
+			name: "index time multi word output phrase",
+			query: search.
+				NewQueryMatchPhrase("new york city tour guide").
+				SetField("product"),
+			expected: []string{"tour"},
+		},
+		{
+			name: "index time multi word output phrase following tokens",
+			query: search.
+				NewQueryMatchPhrase("city tour").
+				SetField("product"),
+			expected: []string{"tour"},
+		},
+		{
+			name: "query time without expansion",
+			query: search.
+				NewQueryMatch("tv").
+				SetField("desc"),
+			expected: []string{},
+		},
+		{
+			name: "query time match",
+			query: search.ExpandSynonyms(
+				search.NewQueryMatch("tv").SetField("desc"),
+				synonyms,
+			),
+			expected: []string{"stand"},
+		},
+		{
+			name: "query time boolean",
+			query: search.ExpandSynonyms(
+				search.NewQueryBoolean().AddMust(
+					search.NewQueryMatch("tv stand").
+						SetField("desc").
+						SetOperator(search.MatchQueryOperatorAnd),
+				),
+				synonyms,
+			),
+			expected: []string{"stand"},
+		},
+		{
+			name: "query time boolean filter",
+			query: search.ExpandSynonyms(
+				search.NewQueryBoolean().AddFilter(
+					search.NewQueryMatch("tv").SetField("desc"),
+				),
+				synonyms,
+			),
+			expected: []string{"stand"},
+		},
+		{
+			name: "query time constant score",
+			query: search.ExpandSynonyms(
+				search.NewQueryConstantScore(
+					search.NewQueryMatchPhrase("big apple pizza").SetField("desc"),
+				),
+				synonyms,
+			),
+			expected: []string{"pizza"},
+		},
+		{
+			name: "query time multi word phrase",
+			query: search.ExpandSynonyms(
+				search.NewQueryMatchPhrase("big apple pizza").SetField("desc"),
+				synonyms,
+			),
+			expected: []string{"pizza"},
+		},
+		{
+			name: "query time in code map",
+			query: search.ExpandSynonyms(
+				search.NewQueryMatch("flatscreen").SetField("desc"),
+				search.NewSynonymsMap(map[string][]string{
+					"flatscreen": {"flatscreen", "television"},
+				}),
+			),
+			expected: []string{"stand"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestLanguages(t *testing.T, engineInitFn SchemaInitFn) {
+	t.Helper()
+
+	langs := []search.Language{
+		search.LanguageEnglish,
+		search.LanguageGerman,
+		search.LanguageFrench,
+		search.LanguageJapanese,
+	}
+
+	schema := search.Schema{
+		Fields: []search.FieldMapping{
+			*search.NewFieldMapping("title", search.FieldTypeText).SetLanguage(search.LanguageGerman),
+			*search.NewFieldMapping("body", search.FieldTypeText).SetLanguages(langs...),
+			*search.NewFieldMapping("nest.body", search.FieldTypeText).SetLanguages(langs...),
+		},
+	}
+
+	engine, indexName, cleanup := engineInitFn(t, schema)
+	defer cleanup()
+
+	docs := []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "en",
+			v:  map[string]interface{}{"body": "The dogs were running through the park with their owners"},
+		},
+		{
+			id: "de",
+			v:  map[string]interface{}{"body": "Die Hunde sind mit ihren Besitzern durch den Park gelaufen"},
+		},
+		{
+			id: "fr",
+			v:  map[string]interface{}{"body": "Les chiens courent dans le parc avec leurs maîtres"},
+		},
+		{
+			id: "ja",
+			v:  map[string]interface{}{"body": "犬たちは飼い主と一緒に東京の公園を走っています"},
+		},
+		{
+			id: "nested de",
+			v: map[string]interface{}{
+				"nest": map[string]interface{}{
+					"body": "Der Park ist für die Hunde der schönste Ort",
+				},
+			},
+		},
+		{
+			id: "title",
+			v:  map[string]interface{}{"title": "Die schönsten Häuser"},
+		},
+	}
+
+	seedIndex(t, engine, indexName, docs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "language preset",
+			query: search.
+				NewQueryMatch("haus").
+				SetField("title"),
+			expected: []string{"title"},
+		},
+		{
+			name: "routed english",
+			query: search.
+				NewQueryMatch("run").
+				SetField(search.LanguageField("body", search.LanguageEnglish)),
+			expected: []string{"en"},
+		},
+		{
+			name: "routed german",
+			query: search.
+				NewQueryMatch("hund").
+				SetField(search.LanguageField("body", search.LanguageGerman)),
+			expected: []string{"de"},
+		},
+		{
+			name: "routed french",
+			query: search.
+				NewQueryMatch("chien").
+				SetField(search.LanguageField("body", search.LanguageFrench)),
+			expected: []string{"fr"},
+		},
+		{
+			name: "routed japanese",
+			query: search.
+				NewQueryMatch("東京").
+				SetField(search.LanguageField("body", search.LanguageJapanese)),
+			expected: []string{"ja"},
+		},
+		{
+			name: "routed nested",
+			query: search.
+				NewQueryMatch("hund").
+				SetField(search.LanguageField("nest.body", search.LanguageGerman)),
+			expected: []string{"nested de"},
+		},
+		{
+			name: "original field is not indexed",
+			query: search.
+				NewQueryMatch("park").
+				SetField("body"),
+			expected: []string{},
+		},
+		{
+			name:     "across languages",
+			query:    search.NewQueryMatchLanguages("park parc", "body", langs...),
+			expected: []string{"de", "en", "fr"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("non text value", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		index := engine.Index(indexName)
+		err := index.Index(ctx, "number", map[string]interface{}{"body": 42})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, search.ErrMappingConflict), err.Error())
+
+		err = index.IndexBatch(ctx, search.Document{ID: "number", Data: map[string]interface{}{"body": 42}})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, search.ErrMappingConflict), err.Error())
+	})
+}
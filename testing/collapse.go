@@ -0,0 +1,190 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCollapse(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "sku1",
+			v:  map[string]interface{}{"text": "red phone", "product": "p1", "price": 10},
+		},
+		{
+			id: "sku2",
+			v:  map[string]interface{}{"text": "blue phone", "product": "p1", "price": 20},
+		},
+		{
+			id: "sku3",
+			v:  map[string]interface{}{"text": "phone case", "product": "p2", "price": 5},
+		},
+		{
+			id: "sku4",
+			v:  map[string]interface{}{"text": "green phone", "product": "p3", "price": 30},
+		},
+		{
+			id: "sku5",
+			v:  map[string]interface{}{"text": "phone charger", "price": 1},
+		},
+		{
+			id: "sku6",
+			v:  map[string]interface{}{"text": "red laptop", "product": "p4", "price": 900},
+		},
+	}...)
+
+	type group struct {
+		key       string
+		total     uint64
+		innerHits []string
+	}
+
+	tests := []struct {
+		name           string
+		opts           []search.SearchOptFn
+		expected       []string
+		expectedGroups []group
+	}{
+		{
+			name: "top hit per group",
+			opts: []search.SearchOptFn{
+				search.WithCollapse(search.CollapseByField("product")),
+				search.WithSort(search.SortByField("price")),
+			},
+			expected: []string{"sku5", "sku3", "sku1", "sku4"},
+			expectedGroups: []group{
+				{key: "", total: 1},
+				{key: "p2", total: 1},
+				{key: "p1", total: 2},
+				{key: "p3", total: 1},
+			},
+		},
+		{
+			name: "descending sort",
+			opts: []search.SearchOptFn{
+				search.WithCollapse(search.CollapseByField("product")),
+				search.WithSort(search.SortByField("price").SetDesc(true)),
+			},
+			expected: []string{"sku4", "sku2", "sku3", "sku5"},
+			expectedGroups: []group{
+				{key: "p3", total: 1},
+				{key: "p1", total: 2},
+				{key: "p2", total: 1},
+				{key: "", total: 1},
+			},
+		},
+		{
+			name: "inner hits",
+			opts: []search.SearchOptFn{
+				search.WithCollapse(search.CollapseByField("product").SetInnerHits(1)),
+				search.WithSort(search.SortByField("price")),
+				search.WithSize(3),
+			},
+			expected: []string{"sku5", "sku3", "sku1"},
+			expectedGroups: []group{
+				{key: "", total: 1},
+				{key: "p2", total: 1},
+				{key: "p1", total: 2, innerHits: []string{"sku2"}},
+			},
+		},
+		{
+			name: "paging across groups",
+			opts: []search.SearchOptFn{
+				search.WithCollapse(search.CollapseByField("product")),
+				search.WithSort(search.SortByField("price")),
+				search.WithFrom(1),
+				search.WithSize(2),
+			},
+			expected: []string{"sku3", "sku1"},
+			expectedGroups: []group{
+				{key: "p2", total: 1},
+				{key: "p1", total: 2},
+			},
+		},
+		{
+			name: "page past the last group",
+			opts: []search.SearchOptFn{
+				search.WithCollapse(search.CollapseByField("product")),
+				search.WithSort(search.SortByField("price")),
+				search.WithFrom(4),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, search.NewQueryMatch("phone").SetField("text"), tt.opts...)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(5), result.Total)
+			assert.Equal(t, uint64(4), result.Groups)
+
+			for i, h := range result.Hits {
+				require.NotNil(t, h.Group)
+				assert.Nil(t, h.Fields)
+
+				expected := tt.expectedGroups[i]
+				assert.Equal(t, expected.key, h.Group.Key)
+				assert.Equal(t, expected.total, h.Group.Total)
+				hasHitIDs(t, h.Group.InnerHits, expected.innerHits...)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("collapse field requested", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMatch("laptop").SetField("text"),
+				search.WithCollapse(search.CollapseByField("product")),
+				search.WithFields("product"),
+			)
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "sku6")
+		assert.Equal(t, "p4", result.Hits[0].Fields["product"])
+	})
+
+	for name, window := range map[string]search.SearchOptFn{
+		"negative size": search.WithSize(-1),
+		"negative from": search.WithFrom(-1),
+	} {
+		window := window
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			_, err := engine.
+				Index(indexName).
+				Search(ctx, search.NewQueryMatch("phone").SetField("text"),
+					search.WithCollapse(search.CollapseByField("product")),
+					window,
+				)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, search.ErrInvalidArgument), "unexpected error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextCancelled(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	docs := make([]search.Document, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		docs = append(docs, search.Document{
+			ID:   fmt.Sprintf("cancelled %d", i),
+			Data: map[string]string{"cancelled": "foo"},
+		})
+	}
+
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, index search.Index) error
+	}{
+		{
+			name: "index",
+			fn: func(ctx context.Context, index search.Index) error {
+				return index.Index(ctx, "cancelled", map[string]string{"cancelled": "foo"})
+			},
+		},
+		{
+			name: "index batch",
+			fn: func(ctx context.Context, index search.Index) error {
+				return index.IndexBatch(ctx, docs...)
+			},
+		},
+		{
+			name: "search",
+			fn: func(ctx context.Context, index search.Index) error {
+				_, err := index.Search(ctx, search.NewQueryMatchAll())
+				return err
+			},
+		},
+		{
+			name: "suggest terms",
+			fn: func(ctx context.Context, index search.Index) error {
+				_, err := index.SuggestTerms(ctx, "foo1", "bax")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			index := engine.Index(indexName)
+
+			t.Run("cancelled", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				start := time.Now()
+				err := tt.fn(ctx, index)
+				require.Error(t, err)
+				assert.Less(t, int64(time.Since(start)), int64(time.Second))
+
+				assert.True(t, errors.Is(err, context.Canceled))
+				var searchErr *search.Error
+				require.True(t, errors.As(err, &searchErr))
+				assert.Equal(t, indexName, searchErr.Index)
+			})
+
+			t.Run("deadline exceeded", func(t *testing.T) {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+				defer cancel()
+
+				err := tt.fn(ctx, index)
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, context.DeadlineExceeded))
+				assert.True(t, errors.Is(err, search.ErrTimeout))
+			})
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("search cancelled while running", func(t *testing.T) {
+		// the context passes the check before the search starts and is
+		// cancelled by the next check of the engine
+		ctx := newCancelAfter(1)
+		defer ctx.cancel()
+
+		_, err := engine.Index(indexName).Search(ctx, search.NewQueryMatchAll())
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("nothing indexed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryTerm("foo").SetField("cancelled"))
+		require.NoError(t, err)
+		assert.Zero(t, result.Total)
+	})
+}
+
+func TestIndexBatch(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	docs := make([]search.Document, 0, 250)
+	for i := 0; i < 250; i++ {
+		docs = append(docs, search.Document{
+			ID:   fmt.Sprintf("doc %03d", i),
+			Data: map[string]interface{}{"text": "batch", "n": i},
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	index := engine.Index(indexName)
+	require.NoError(t, index.IndexBatch(ctx, docs...))
+
+	result, err := index.Search(ctx, search.NewQueryMatch("batch").SetField("text"),
+		search.WithSort(search.SortByField("n").SetDesc(true)),
+		search.WithSize(2),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(250), result.Total)
+	hasHitIDs(t, result.Hits, "doc 249", "doc 248")
+
+	t.Run("cancelled while running", func(t *testing.T) {
+		docs := make([]search.Document, 0, 250)
+		for i := 0; i < 250; i++ {
+			docs = append(docs, search.Document{
+				ID:   fmt.Sprintf("partial %03d", i),
+				Data: map[string]interface{}{"text": "partial", "n": i},
+			})
+		}
+
+		ctx := newCancelAfter(150)
+		defer ctx.cancel()
+
+		err := index.IndexBatch(ctx, docs...)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		// the documents applied before the cancellation stay indexed
+		result, err := index.Search(context.Background(), search.NewQueryMatch("partial").SetField("text"),
+			search.WithSort(search.SortByField("n")),
+			search.WithSize(len(docs)),
+		)
+		require.NoError(t, err)
+		require.NotZero(t, result.Total)
+		require.Less(t, result.Total, uint64(len(docs)))
+		for i, h := range result.Hits {
+			assert.Equal(t, docs[i].ID, h.ID)
+		}
+	})
+}
+
+// cancelAfter is a context that cancels itself once the engine checked it
+// more than n times, cancelling the operation while it runs.
+type cancelAfter struct {
+	context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	checks int
+	n      int
+}
+
+func newCancelAfter(n int) *cancelAfter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &cancelAfter{Context: ctx, cancel: cancel, n: n}
+}
+
+func (c *cancelAfter) Done() <-chan struct{} {
+	c.check()
+	return c.Context.Done()
+}
+
+func (c *cancelAfter) Err() error {
+	c.check()
+	return c.Context.Err()
+}
+
+func (c *cancelAfter) check() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks++
+	if c.checks > c.n {
+		c.cancel()
+	}
+}
@@ -0,0 +1,178 @@
+package testing
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, productDocs...)
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{id: "tie a", v: map[string]interface{}{"text": "tablet", "price": 500}},
+		{id: "tie b", v: map[string]interface{}{"text": "tablet", "price": 500}},
+		{id: "tie c", v: map[string]interface{}{"text": "tablet", "price": 500}},
+	}...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		opts     []search.SearchOptFn
+		expected []string
+	}{
+		{
+			name:  "field sort",
+			query: search.NewQueryMatchAll(),
+			opts: []search.SearchOptFn{
+				search.WithSort(search.SortByField("price")),
+				search.WithSize(2),
+			},
+			expected: []string{"cheap phone", "phone case", "tie a", "tie b", "tie c", "pricey phone", "laptop"},
+		},
+		{
+			name:  "descending field sort",
+			query: search.NewQueryMatchAll(),
+			opts: []search.SearchOptFn{
+				search.WithSort(search.SortByField("price").SetDesc(true)),
+				search.WithSize(3),
+			},
+			expected: []string{"laptop", "pricey phone", "tie a", "tie b", "tie c", "phone case", "cheap phone"},
+		},
+		{
+			name:     "score ties",
+			query:    search.NewQueryMatch("tablet").SetField("text"),
+			opts:     []search.SearchOptFn{search.WithSize(1)},
+			expected: []string{"tie a", "tie b", "tie c"},
+		},
+		{
+			name:     "score sort",
+			query:    search.NewQueryMatch("phone").SetField("text"),
+			opts:     []search.SearchOptFn{search.WithSize(1)},
+			expected: []string{"cheap phone", "pricey phone", "phone case"},
+		},
+		{
+			name:  "page larger than hits",
+			query: search.NewQueryMatch("phone").SetField("text"),
+			opts: []search.SearchOptFn{
+				search.WithSort(search.SortByID().SetDesc(true)),
+				search.WithSize(10),
+			},
+			expected: []string{"pricey phone", "phone case", "cheap phone"},
+		},
+		{
+			name:     "no hits",
+			query:    search.NewQueryMatchNone(),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			cursor := search.NewCursor(ctx, engine.Index(indexName), tt.query, tt.opts...)
+
+			ids := []string{}
+			for cursor.Next() {
+				ids = append(ids, cursor.Hit().ID)
+			}
+			require.NoError(t, cursor.Err())
+
+			assert.Equal(t, tt.expected, ids)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("search after", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		sorts := []search.Sort{search.SortByField("price"), search.SortByID()}
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMatchAll(), search.WithSort(sorts...), search.WithSize(3))
+		require.NoError(t, err)
+		hasHitIDs(t, result.Hits, "cheap phone", "phone case", "tie a")
+
+		result, err = engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMatchAll(),
+				search.WithSort(sorts...),
+				search.WithSize(3),
+				search.WithSearchAfter(result.Hits[2].Sort...),
+			)
+		require.NoError(t, err)
+		hasHitIDs(t, result.Hits, "tie b", "tie c", "pricey phone")
+	})
+
+	t.Run("score sort values", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMatch("phone").SetField("text"),
+				search.WithSort(search.SortByScore()),
+			)
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 3)
+		require.NotEqual(t, result.Hits[0].Score, result.Hits[2].Score)
+
+		for _, h := range result.Hits {
+			require.Len(t, h.Sort, 1, h.ID)
+			score, err := strconv.ParseFloat(h.Sort[0], 64)
+			require.NoError(t, err)
+			assert.InDelta(t, h.Score, score, 1e-9, h.ID)
+		}
+	})
+
+	t.Run("search after requires a value per sort", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMatchAll(),
+				search.WithSort(search.SortByField("price"), search.SortByID()),
+				search.WithSearchAfter("laptop"),
+			)
+		require.Error(t, err)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cursor := search.NewCursor(ctx, engine.Index(indexName), search.NewQueryMatchAll(), search.WithSize(2))
+		require.True(t, cursor.Next())
+
+		cancel()
+		assert.False(t, cursor.Next())
+		assert.Equal(t, context.Canceled, cursor.Err())
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		cursor := search.NewCursor(ctx, engine.Index(indexName), search.NewQueryMatchAll(),
+			search.WithCollapse(search.CollapseByField("price")),
+		)
+		assert.False(t, cursor.Next())
+		assert.Error(t, cursor.Err())
+	})
+}
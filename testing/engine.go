@@ -2,6 +2,8 @@ package testing
 
 import (
 	"context"
+	"errors"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +15,9 @@ import (
 
 type InitFn func(*testing.T) (engine search.Engine, name string, cleanup func())
 
+// SchemaInitFn initializes an engine with an index setup from the schema.
+type SchemaInitFn func(t *testing.T, schema search.Schema) (engine search.Engine, name string, cleanup func())
+
 var simpleDocs = []struct {
 	id string
 	v  interface{}
@@ -53,14 +58,94 @@ func TestSearchQueries(t *testing.T, engineInitFn InitFn) {
 		name   string
 		testFn func(t *testing.T, engineInitFn InitFn)
 	}{
+		{
+			name:   "boolean",
+			testFn: TestQueryBoolean,
+		},
+		{
+			name:   "boolean filter",
+			testFn: TestQueryBooleanFilter,
+		},
 		{
 			name:   "bool field",
 			testFn: TestQueryBoolField,
 		},
+		{
+			name:   "boosting",
+			testFn: TestQueryBoosting,
+		},
+		{
+			name:   "cancelled context",
+			testFn: TestContextCancelled,
+		},
+		{
+			name:   "collapse",
+			testFn: TestQueryCollapse,
+		},
+		{
+			name:   "conjunction",
+			testFn: TestQueryConjunction,
+		},
+		{
+			name:   "constant score",
+			testFn: TestQueryConstantScore,
+		},
+		{
+			name:   "cursor",
+			testFn: TestCursor,
+		},
 		{
 			name:   "date range",
 			testFn: TestQueryDateRange,
 		},
+		{
+			name:   "did you mean",
+			testFn: TestQueryDidYouMean,
+		},
+		{
+			name:   "disjunction",
+			testFn: TestQueryDisjunction,
+		},
+		{
+			name:   "dis max",
+			testFn: TestQueryDisMax,
+		},
+		{
+			name:   "engine concurrency",
+			testFn: TestEngineConcurrency,
+		},
+		{
+			name:   "errors",
+			testFn: TestErrors,
+		},
+		{
+			name:   "exists",
+			testFn: TestQueryExists,
+		},
+		{
+			name:   "explain",
+			testFn: TestExplain,
+		},
+		{
+			name:   "function score",
+			testFn: TestQueryFunctionScore,
+		},
+		{
+			name:   "fuzzy",
+			testFn: TestQueryFuzzy,
+		},
+		{
+			name:   "index batch",
+			testFn: TestIndexBatch,
+		},
+		{
+			name:   "index management",
+			testFn: TestIndexManagement,
+		},
+		{
+			name:   "lookup index",
+			testFn: TestLookupIndex,
+		},
 		{
 			name:   "match",
 			testFn: TestQueryMatch,
@@ -77,6 +162,14 @@ func TestSearchQueries(t *testing.T, engineInitFn InitFn) {
 			name:   "match phrase",
 			testFn: TestQueryMatchPhrase,
 		},
+		{
+			name:   "more like this",
+			testFn: TestQueryMoreLikeThis,
+		},
+		{
+			name:   "multi match",
+			testFn: TestQueryMultiMatch,
+		},
 		{
 			name:   "numeric range",
 			testFn: TestQueryNumericRange,
@@ -85,6 +178,14 @@ func TestSearchQueries(t *testing.T, engineInitFn InitFn) {
 			name:   "prefix",
 			testFn: TestQueryPrefix,
 		},
+		{
+			name:   "profile",
+			testFn: TestProfile,
+		},
+		{
+			name:   "suggest terms",
+			testFn: TestSuggestTerms,
+		},
 		{
 			name:   "term",
 			testFn: TestQueryTerm,
@@ -93,6 +194,10 @@ func TestSearchQueries(t *testing.T, engineInitFn InitFn) {
 			name:   "term range",
 			testFn: TestQueryTermRange,
 		},
+		{
+			name:   "terms",
+			testFn: TestQueryTerms,
+		},
 	}
 
 	for _, tt := range queryTests {
@@ -102,6 +207,216 @@ func TestSearchQueries(t *testing.T, engineInitFn InitFn) {
 	}
 }
 
+func TestQueryBoolean(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "should",
+			query: search.
+				NewQueryBoolean().
+				AddShould(search.NewQueryTerm("bug"), search.NewQueryTerm("baz")),
+			expected: []string{"bar", "foo1"},
+		},
+		{
+			name: "should min",
+			query: search.
+				NewQueryBoolean().
+				AddShould(
+					search.NewQueryTerm("bar"),
+					search.NewQueryTerm("baz"),
+					search.NewQueryTerm("bug"),
+					search.NewQueryTerm("fit"),
+				).
+				SetMinShould(2),
+			expected: []string{"foo1", "fit"},
+		},
+		{
+			name: "should min exceeds matches",
+			query: search.
+				NewQueryBoolean().
+				AddShould(
+					search.NewQueryTerm("bar"),
+					search.NewQueryTerm("baz"),
+					search.NewQueryTerm("bug"),
+					search.NewQueryTerm("fit"),
+				).
+				SetMinShould(3),
+			expected: []string{},
+		},
+		{
+			name: "must with optional should",
+			query: search.
+				NewQueryBoolean().
+				AddMust(search.NewQueryTerm("bar")).
+				AddShould(search.NewQueryTerm("bug")),
+			expected: []string{"foo1", "foo2", "fit"},
+		},
+		{
+			name: "must with required should",
+			query: search.
+				NewQueryBoolean().
+				AddMust(search.NewQueryTerm("bar")).
+				AddShould(search.NewQueryTerm("bug")).
+				SetMinShould(1),
+			expected: []string{"foo1"},
+		},
+		{
+			name: "must not",
+			query: search.
+				NewQueryBoolean().
+				AddMust(search.NewQueryTerm("bar")).
+				AddMustNot(search.NewQueryTerm("fit")),
+			expected: []string{"foo2", "foo1"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+var productDocs = []struct {
+	id string
+	v  interface{}
+}{
+	{
+		id: "cheap phone",
+		v:  map[string]interface{}{"text": "phone", "price": 10},
+	},
+	{
+		id: "phone case",
+		v:  map[string]interface{}{"text": "phone case", "price": 20},
+	},
+	{
+		id: "pricey phone",
+		v:  map[string]interface{}{"text": "phone phone flagship", "price": 900},
+	},
+	{
+		id: "laptop",
+		v:  map[string]interface{}{"text": "laptop", "price": 1000},
+	},
+}
+
+func TestQueryBooleanFilter(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, productDocs...)
+
+	index := engine.Index(indexName)
+	phone := search.NewQueryMatch("phone").SetField("text")
+	cheap := search.NewQueryNumericRange().SetMax(100).SetField("price")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	unfiltered, err := index.Search(ctx, search.NewQueryBoolean().AddMust(phone))
+	require.NoError(t, err)
+	unfilteredScores := hitScores(unfiltered.Hits)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "filter keeps scores",
+			query: search.NewQueryBoolean().
+				AddMust(phone).
+				AddFilter(cheap),
+			expected: []string{"cheap phone", "phone case"},
+		},
+		{
+			name: "multiple filters",
+			query: search.NewQueryBoolean().
+				AddMust(phone).
+				AddFilter(cheap, search.NewQueryTerm("case").SetField("text")),
+			expected: []string{"phone case"},
+		},
+		{
+			name: "filter with must not",
+			query: search.NewQueryBoolean().
+				AddMust(phone).
+				AddMustNot(search.NewQueryTerm("case").SetField("text")).
+				AddFilter(cheap),
+			expected: []string{"cheap phone"},
+		},
+		{
+			name: "filter without matches",
+			query: search.NewQueryBoolean().
+				AddMust(phone).
+				AddFilter(search.NewQueryTerm("tablet").SetField("text")),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := index.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+			for _, h := range result.Hits {
+				assert.InDelta(t, unfilteredScores[h.ID], h.Score, 1e-9, h.ID)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("must clause changes scores", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := index.Search(ctx, search.NewQueryBoolean().AddMust(phone, cheap))
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "cheap phone", "phone case")
+		assert.NotEqual(t, unfilteredScores["cheap phone"], result.Hits[0].Score)
+	})
+
+	t.Run("filter only", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := index.Search(ctx, search.NewQueryBoolean().AddFilter(
+			search.NewQueryNumericRange().SetMin(500).SetField("price"),
+		))
+		require.NoError(t, err)
+
+		scores := hitScores(result.Hits)
+		require.Len(t, scores, 2)
+		assert.Equal(t, scores["pricey phone"], scores["laptop"])
+	})
+}
+
 func TestQueryBoolField(t *testing.T, engineInitFn InitFn) {
 	t.Helper()
 
@@ -189,61 +504,136 @@ func TestQueryBoolField(t *testing.T, engineInitFn InitFn) {
 	}
 }
 
-func TestQueryDateRange(t *testing.T, engineInitFn InitFn) {
+func TestQueryBoosting(t *testing.T, engineInitFn InitFn) {
 	t.Helper()
 
 	engine, indexName, cleanup := engineInitFn(t)
 	defer cleanup()
 
-	now, day := time.Now(), 24*time.Hour
-
-	docs := []struct {
+	seedIndex(t, engine, indexName, []struct {
 		id string
 		v  interface{}
 	}{
 		{
-			id: "bar 30 days ago",
-			v:  map[string]interface{}{"bar": now.Add(-30 * day)},
-		},
-		{
-			id: "bar 20 days ago",
-			v:  map[string]interface{}{"bar": now.Add(-20 * day)},
-		},
-		{
-			id: "bar 10 days ago",
-			v:  map[string]interface{}{"bar": now.Add(-10 * day)},
-		},
-		{
-			id: "bar today",
-			v:  map[string]interface{}{"bar": now},
-		},
-		{
-			id: "baz 10 days ago",
-			v:  map[string]interface{}{"baz": now.Add(-10 * day)},
+			id: "pie",
+			v:  map[string]interface{}{"text": "apple pie"},
 		},
 		{
-			id: "baz today",
-			v:  map[string]interface{}{"baz": now},
+			id: "computer",
+			v:  map[string]interface{}{"text": "apple computer company"},
 		},
 		{
-			id: "nested 10 days ago",
-			v: map[string]interface{}{
-				"nested": map[string]interface{}{
-					"date": now.Add(-10 * day),
-				},
-			},
+			id: "banana",
+			v:  map[string]interface{}{"text": "banana pie"},
 		},
+	}...)
+
+	index := engine.Index(indexName)
+	apple := search.NewQueryMatch("apple").SetField("text")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	plain, err := index.Search(ctx, apple)
+	require.NoError(t, err)
+	hasHitIDs(t, plain.Hits, "pie", "computer")
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+		factor   float64
+	}{
 		{
-			id: "nested today",
-			v: map[string]interface{}{
-				"nested": map[string]interface{}{
-					"date": now,
-				},
-			},
+			name: "negative matches are demoted",
+			query: search.NewQueryBoosting(
+				apple,
+				search.NewQueryMatch("pie").SetField("text"),
+				0.2,
+			),
+			expected: []string{"computer", "pie"},
+			factor:   0.2,
+		},
+		{
+			name: "negative without matches keeps scores",
+			query: search.NewQueryBoosting(
+				apple,
+				search.NewQueryMatch("cherry").SetField("text"),
+				0.2,
+			),
+			expected: []string{"pie", "computer"},
+			factor:   1,
+		},
+		{
+			name: "negative boost of zero",
+			query: search.NewQueryBoosting(
+				apple,
+				search.NewQueryMatch("pie").SetField("text"),
+				0,
+			),
+			expected: []string{"computer", "pie"},
+			factor:   0,
 		},
 	}
 
-	seedIndex(t, engine, indexName, docs...)
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := index.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+
+			scores := hitScores(result.Hits)
+			assert.InDelta(t, plain.Hits[0].Score*tt.factor, scores["pie"], 1e-9)
+			assert.InDelta(t, plain.Hits[1].Score, scores["computer"], 1e-9)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("boolean negative demotes every match", func(t *testing.T) {
+		seedIndex(t, engine, indexName, []struct {
+			id string
+			v  interface{}
+		}{
+			{id: "tart 1", v: map[string]interface{}{"text": "red tart"}},
+			{id: "tart 2", v: map[string]interface{}{"text": "blue tart"}},
+			{id: "tart 3", v: map[string]interface{}{"text": "red tart"}},
+			{id: "tart 4", v: map[string]interface{}{"text": "blue tart"}},
+		}...)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tart := search.NewQueryMatch("tart").SetField("text")
+		plain, err := index.Search(ctx, tart)
+		require.NoError(t, err)
+		require.Len(t, plain.Hits, 4)
+
+		result, err := index.Search(ctx, search.NewQueryBoosting(
+			tart,
+			search.NewQueryBoolean().AddMust(search.NewQueryMatch("red blue").SetField("text")),
+			0.2,
+		))
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "tart 1", "tart 2", "tart 3", "tart 4")
+		for _, h := range result.Hits {
+			assert.InDelta(t, plain.Hits[0].Score*0.2, h.Score, 1e-9, h.ID)
+		}
+	})
+}
+
+func TestQueryConjunction(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
 
 	tests := []struct {
 		name     string
@@ -251,72 +641,872 @@ func TestQueryDateRange(t *testing.T, engineInitFn InitFn) {
 		expected []string
 	}{
 		{
-			name:  "30 day range",
-			query: search.NewQueryDataRange(now.Add(-31*day), now.Add(1*day)),
-			expected: []string{
-				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago", "bar today",
-				"baz 10 days ago", "baz today",
-				"nested 10 days ago", "nested today",
-			},
+			name: "single",
+			query: search.
+				NewQueryConjunction(search.NewQueryTerm("bar")),
+			expected: []string{"foo2", "foo1", "fit"},
+		},
+		{
+			name: "all must match",
+			query: search.
+				NewQueryConjunction(search.NewQueryTerm("bar")).
+				AddConjuncts(search.NewQueryTerm("fit")),
+			expected: []string{"fit"},
+		},
+		{
+			name: "no matches",
+			query: search.NewQueryConjunction(
+				search.NewQueryTerm("bar"),
+				search.NewQueryTerm("baz"),
+			),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestQueryConstantScore(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, productDocs...)
+
+	phone := search.NewQueryMatch("phone").SetField("text")
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected map[string]float64
+	}{
+		{
+			name:     "defaults to a score of one",
+			query:    search.NewQueryConstantScore(phone),
+			expected: map[string]float64{"cheap phone": 1, "phone case": 1, "pricey phone": 1},
+		},
+		{
+			name:     "boost is the score",
+			query:    search.NewQueryConstantScore(phone).SetBoost(3),
+			expected: map[string]float64{"cheap phone": 3, "phone case": 3, "pricey phone": 3},
+		},
+		{
+			name:     "nil query matches all",
+			query:    search.NewQueryConstantScore(nil).SetBoost(2),
+			expected: map[string]float64{"cheap phone": 2, "phone case": 2, "pricey phone": 2, "laptop": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			scores := hitScores(result.Hits)
+			require.Len(t, scores, len(tt.expected))
+			for id, score := range tt.expected {
+				assert.InDelta(t, score, scores[id], 1e-9, id)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("ranks above scored clauses", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryBoolean().AddShould(
+				phone,
+				search.NewQueryConstantScore(search.NewQueryTerm("case").SetField("text")).SetBoost(10),
+			))
+		require.NoError(t, err)
+
+		require.NotEmpty(t, result.Hits)
+		assert.Equal(t, "phone case", result.Hits[0].ID)
+	})
+}
+
+func TestQueryDateRange(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	now, day := time.Now(), 24*time.Hour
+
+	docs := []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "bar 30 days ago",
+			v:  map[string]interface{}{"bar": now.Add(-30 * day)},
+		},
+		{
+			id: "bar 20 days ago",
+			v:  map[string]interface{}{"bar": now.Add(-20 * day)},
+		},
+		{
+			id: "bar 10 days ago",
+			v:  map[string]interface{}{"bar": now.Add(-10 * day)},
+		},
+		{
+			id: "bar today",
+			v:  map[string]interface{}{"bar": now},
+		},
+		{
+			id: "baz 10 days ago",
+			v:  map[string]interface{}{"baz": now.Add(-10 * day)},
+		},
+		{
+			id: "baz today",
+			v:  map[string]interface{}{"baz": now},
+		},
+		{
+			id: "nested 10 days ago",
+			v: map[string]interface{}{
+				"nested": map[string]interface{}{
+					"date": now.Add(-10 * day),
+				},
+			},
+		},
+		{
+			id: "nested today",
+			v: map[string]interface{}{
+				"nested": map[string]interface{}{
+					"date": now,
+				},
+			},
+		},
+	}
+
+	seedIndex(t, engine, indexName, docs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:  "30 day range",
+			query: search.NewQueryDataRange(now.Add(-31*day), now.Add(1*day)),
+			expected: []string{
+				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago", "bar today",
+				"baz 10 days ago", "baz today",
+				"nested 10 days ago", "nested today",
+			},
+		},
+		{
+			name:  "defaults to inclusive start and exclusive end",
+			query: search.NewQueryDataRange(now.Add(-30*day), now),
+			expected: []string{
+				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
+				"baz 10 days ago",
+				"nested 10 days ago",
+			},
+		},
+		{
+			name: "30 day range exclusive start and end",
+			query: search.
+				NewQueryDataRange(now.Add(-30*day), now).
+				SetInclusiveStart(false).
+				SetInclusiveEnd(false),
+			expected: []string{
+				"bar 20 days ago", "bar 10 days ago",
+				"baz 10 days ago",
+				"nested 10 days ago",
+			},
+		},
+		{
+			name: "30 day range inclusive start and exclusive end",
+			query: search.
+				NewQueryDataRange(now.Add(-30*day), now).
+				SetInclusiveEnd(false),
+			expected: []string{
+				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
+				"baz 10 days ago",
+				"nested 10 days ago",
+			},
+		},
+		{
+			name: "30 day range inclusive start and exclusive end",
+			query: search.
+				NewQueryDataRange(now.Add(-30*day), now).
+				SetInclusiveStart(true).
+				SetInclusiveEnd(false),
+			expected: []string{
+				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
+				"baz 10 days ago",
+				"nested 10 days ago",
+			},
+		},
+		{
+			name: "nested to inclusive start and exclusive end",
+			query: search.
+				NewQueryDataRange(now.Add(-30*day), now).
+				SetField("nested.date"),
+			expected: []string{"nested 10 days ago"},
+		},
+		{
+			name: "nested to inclusive start and inclusive end",
+			query: search.
+				NewQueryDataRange(now.Add(-30*day), now).
+				SetField("nested.date").
+				SetInclusiveEnd(true),
+			expected: []string{"nested 10 days ago", "nested today"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestQueryDisjunction(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "any",
+			query: search.NewQueryDisjunction(
+				search.NewQueryTerm("bug"),
+				search.NewQueryTerm("baz"),
+			),
+			expected: []string{"bar", "foo1"},
+		},
+		{
+			name: "min",
+			query: search.
+				NewQueryDisjunction(
+					search.NewQueryTerm("bar"),
+					search.NewQueryTerm("bug"),
+				).
+				AddDisjuncts(search.NewQueryTerm("foo")).
+				SetMin(2),
+			expected: []string{"foo1", "fit"},
+		},
+		{
+			name: "min exceeds matches",
+			query: search.
+				NewQueryDisjunction(
+					search.NewQueryTerm("bar"),
+					search.NewQueryTerm("bug"),
+					search.NewQueryTerm("foo"),
+				).
+				SetMin(3),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestQueryDisMax(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "rabbits",
+			v: map[string]interface{}{
+				"title": "quick brown rabbits",
+				"body":  "brown rabbits are commonly seen",
+			},
+		},
+		{
+			id: "fox",
+			v: map[string]interface{}{
+				"title": "keeping pets healthy",
+				"body":  "my quick brown fox eats rabbits on a regular basis",
+			},
+		},
+		{
+			id: "pets",
+			v: map[string]interface{}{
+				"title": "keeping pets",
+				"body":  "pets are fun",
+			},
+		},
+		{
+			id: "color 1",
+			v:  map[string]interface{}{"color": "red"},
+		},
+		{
+			id: "color 2",
+			v:  map[string]interface{}{"color": "red"},
+		},
+		{
+			id: "color 3",
+			v:  map[string]interface{}{"color": "red", "label": "target"},
+		},
+		{
+			id: "color 4",
+			v:  map[string]interface{}{"color": "red"},
+		},
+	}...)
+
+	index := engine.Index(indexName)
+	fields := func(match string) []search.Query {
+		return []search.Query{
+			search.NewQueryMatch(match).SetField("title"),
+			search.NewQueryMatch(match).SetField("body"),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:     "boolean sums the fields",
+			query:    search.NewQueryBoolean().AddShould(fields("brown fox")...),
+			expected: []string{"rabbits", "fox"},
+		},
+		{
+			name:     "best field wins",
+			query:    search.NewQueryDisMax(fields("brown fox")...),
+			expected: []string{"fox", "rabbits"},
+		},
+		{
+			name: "added disjuncts",
+			query: search.
+				NewQueryDisMax(fields("brown fox")...).
+				AddDisjuncts(search.NewQueryMatch("pets").SetField("body")),
+			expected: []string{"pets", "fox", "rabbits"},
+		},
+		{
+			name:     "no matches",
+			query:    search.NewQueryDisMax(fields("cat")...),
+			expected: []string{},
+		},
+		{
+			name: "advanced boolean disjunct",
+			query: search.NewQueryConjunction(
+				search.NewQueryDisMax(
+					search.NewQueryBoolean().AddMust(search.NewQueryMatch("red blue").SetField("color")),
+					search.NewQueryMatch("red").SetField("label"),
+				),
+				search.NewQueryMatch("target").SetField("label"),
+			),
+			expected: []string{"color 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := index.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("tie breaker adds the other fields", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		best, err := index.Search(ctx, search.NewQueryDisMax(fields("rabbits")...))
+		require.NoError(t, err)
+		tied, err := index.Search(ctx, search.NewQueryDisMax(fields("rabbits")...).SetTieBreaker(0.5))
+		require.NoError(t, err)
+
+		bestScores, tiedScores := hitScores(best.Hits), hitScores(tied.Hits)
+		assert.Greater(t, tiedScores["rabbits"], bestScores["rabbits"])
+		assert.InDelta(t, bestScores["fox"], tiedScores["fox"], 1e-9)
+	})
+}
+
+func TestQueryExists(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "discounted",
+			v: map[string]interface{}{
+				"price":    10,
+				"discount": 2,
+				"nest":     map[string]interface{}{"first": "bar"},
+			},
+		},
+		{
+			id: "owned",
+			v: map[string]interface{}{
+				"price": 20,
+				"owner": "bob",
+			},
+		},
+		{
+			id: "nested",
+			v: map[string]interface{}{
+				"nest": map[string]interface{}{
+					"first":  "baz",
+					"second": "qux",
+				},
+			},
+		},
+	}...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:     "numeric field",
+			query:    search.NewQueryExists("discount"),
+			expected: []string{"discounted"},
+		},
+		{
+			name:     "text field",
+			query:    search.NewQueryExists("owner"),
+			expected: []string{"owned"},
+		},
+		{
+			name:     "nested field",
+			query:    search.NewQueryExists("nest.first"),
+			expected: []string{"discounted", "nested"},
+		},
+		{
+			name:     "unknown field",
+			query:    search.NewQueryExists("nope"),
+			expected: []string{},
+		},
+		{
+			name:     "missing field",
+			query:    search.NewQueryMissing("owner"),
+			expected: []string{"discounted", "nested"},
+		},
+		{
+			name:     "missing nested field",
+			query:    search.NewQueryMissing("nest.second"),
+			expected: []string{"discounted", "owned"},
+		},
+		{
+			name:     "missing unknown field",
+			query:    search.NewQueryMissing("nope"),
+			expected: []string{"discounted", "nested", "owned"},
+		},
+		{
+			name: "combined with other queries",
+			query: search.NewQueryBoolean().
+				AddMust(search.NewQueryExists("price")).
+				AddMustNot(search.NewQueryExists("discount")),
+			expected: []string{"owned"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hitIDs := make([]string, 0, len(result.Hits))
+			for _, h := range result.Hits {
+				hitIDs = append(hitIDs, h.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, hitIDs)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestQueryFunctionScore(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	now, day := time.Now(), 24*time.Hour
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "old popular",
+			v: map[string]interface{}{
+				"text":       "phone case",
+				"popularity": 100,
+				"price":      300,
+				"published":  now.Add(-60 * day),
+			},
+		},
+		{
+			id: "new niche",
+			v: map[string]interface{}{
+				"text":       "phone case",
+				"popularity": 5,
+				"price":      95,
+				"published":  now.Add(-day),
+			},
+		},
+		{
+			id: "mid",
+			v: map[string]interface{}{
+				"text":       "phone case",
+				"popularity": 30,
+				"price":      150,
+				"published":  now.Add(-20 * day),
+			},
+		},
+	}...)
+
+	index := engine.Index(indexName)
+	phone := search.NewQueryMatch("phone").SetField("text")
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "field value factor",
+			query: search.NewQueryFunctionScore(phone,
+				search.NewFunctionFieldValueFactor("popularity"),
+			),
+			expected: []string{"old popular", "mid", "new niche"},
+		},
+		{
+			name: "field value factor with modifier",
+			query: search.NewQueryFunctionScore(phone,
+				search.NewFunctionFieldValueFactor("popularity").
+					SetModifier(search.ModifierReciprocal),
+			),
+			expected: []string{"new niche", "mid", "old popular"},
+		},
+		{
+			name: "gauss decay on date",
+			query: search.NewQueryFunctionScore(phone,
+				search.NewFunctionDateDecay(search.FunctionTypeDecayGauss, "published", now, 10*day),
+			),
+			expected: []string{"new niche", "mid", "old popular"},
+		},
+		{
+			name: "linear decay on number",
+			query: search.NewQueryFunctionScore(phone,
+				search.NewFunctionDecay(search.FunctionTypeDecayLinear, "price", 100, 50),
+			),
+			expected: []string{"new niche", "mid", "old popular"},
+		},
+		{
+			name: "exp decay on number",
+			query: search.NewQueryFunctionScore(phone,
+				search.NewFunctionDecay(search.FunctionTypeDecayExp, "price", 300, 100),
+			),
+			expected: []string{"old popular", "mid", "new niche"},
+		},
+		{
+			name: "recency outweighs popularity",
+			query: search.
+				NewQueryFunctionScore(phone,
+					search.NewFunctionFieldValueFactor("popularity").SetModifier(search.ModifierLog1p),
+					search.NewFunctionDateDecay(search.FunctionTypeDecayExp, "published", now, 5*day).SetWeight(10),
+				).
+				SetScoreMode(search.ScoreModeSum),
+			expected: []string{"new niche", "mid", "old popular"},
+		},
+		{
+			name: "nil query matches all",
+			query: search.NewQueryFunctionScore(nil,
+				search.NewFunctionFieldValueFactor("popularity"),
+			),
+			expected: []string{"old popular", "mid", "new niche"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := index.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	scoreTests := []struct {
+		name     string
+		query    search.Query
+		expected map[string]float64
+	}{
+		{
+			name: "weights summed and replaced",
+			query: search.
+				NewQueryFunctionScore(phone,
+					search.NewFunctionWeight(2),
+					search.NewFunctionWeight(3),
+				).
+				SetScoreMode(search.ScoreModeSum).
+				SetBoostMode(search.BoostModeReplace),
+			expected: map[string]float64{"old popular": 5, "new niche": 5, "mid": 5},
+		},
+		{
+			name: "max score mode",
+			query: search.
+				NewQueryFunctionScore(phone,
+					search.NewFunctionWeight(2),
+					search.NewFunctionWeight(3),
+				).
+				SetScoreMode(search.ScoreModeMax).
+				SetBoostMode(search.BoostModeReplace),
+			expected: map[string]float64{"old popular": 3, "new niche": 3, "mid": 3},
+		},
+		{
+			name: "missing field value",
+			query: search.
+				NewQueryFunctionScore(phone,
+					search.NewFunctionFieldValueFactor("discount").SetMissing(4).SetFactor(2),
+				).
+				SetBoostMode(search.BoostModeReplace),
+			expected: map[string]float64{"old popular": 8, "new niche": 8, "mid": 8},
+		},
+		{
+			name: "boosted",
+			query: search.
+				NewQueryFunctionScore(phone,
+					search.NewFunctionFieldValueFactor("popularity"),
+				).
+				SetBoostMode(search.BoostModeReplace).
+				SetBoost(2),
+			expected: map[string]float64{"old popular": 200, "new niche": 10, "mid": 60},
+		},
+	}
+
+	for _, tt := range scoreTests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := index.Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			scores := hitScores(result.Hits)
+			require.Len(t, scores, len(tt.expected))
+			for id, score := range tt.expected {
+				assert.InDelta(t, score, scores[id], 1e-9, id)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("multiplies the query score", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		plain, err := index.Search(ctx, phone)
+		require.NoError(t, err)
+		result, err := index.Search(ctx, search.NewQueryFunctionScore(phone,
+			search.NewFunctionFieldValueFactor("popularity").SetModifier(search.ModifierLog1p),
+		))
+		require.NoError(t, err)
+
+		plainScores, scores := hitScores(plain.Hits), hitScores(result.Hits)
+		assert.InDelta(t, plainScores["old popular"]*math.Log10(101), scores["old popular"], 1e-9)
+		assert.InDelta(t, plainScores["mid"]*math.Log10(31), scores["mid"], 1e-9)
+	})
+
+	t.Run("random score is stable for a seed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		q := search.
+			NewQueryFunctionScore(phone, search.NewFunctionRandomScore(42)).
+			SetBoostMode(search.BoostModeReplace)
+
+		first, err := index.Search(ctx, q)
+		require.NoError(t, err)
+		second, err := index.Search(ctx, q)
+		require.NoError(t, err)
+
+		assert.Equal(t, hitScores(first.Hits), hitScores(second.Hits))
+		for _, h := range first.Hits {
+			assert.True(t, h.Score >= 0 && h.Score < 1, h.Score)
+		}
+	})
+
+	t.Run("field value without a finite score fails", func(t *testing.T) {
+		seedIndex(t, engine, indexName, []struct {
+			id string
+			v  interface{}
+		}{
+			{id: "negative", v: map[string]interface{}{"text": "broken", "popularity": -5}},
+		}...)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := index.Search(ctx, search.NewQueryFunctionScore(
+			search.NewQueryMatch("broken").SetField("text"),
+			search.NewFunctionFieldValueFactor("popularity").SetModifier(search.ModifierLog),
+		))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrInvalidQuery), err.Error())
+	})
+
+	t.Run("invalid decay", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := index.Search(ctx, search.NewQueryFunctionScore(phone,
+			search.NewFunctionDecay(search.FunctionTypeDecayExp, "price", 100, 50).SetDecay(1),
+		))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrInvalidQuery), err.Error())
+	})
+}
+
+func TestQueryFuzzy(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:     "basic 1 off",
+			query:    search.NewQueryFuzzy("bax"),
+			expected: []string{"bar", "foo2", "foo1", "fit"},
+		},
+		{
+			name:     "no matches",
+			query:    search.NewQueryFuzzy("xxx"),
+			expected: []string{},
+		},
+		{
+			name: "2 off",
+			query: search.
+				NewQueryFuzzy("fobbaz").
+				SetFuzziness(2),
+			expected: []string{"baz"},
 		},
 		{
-			name:  "defaults to inclusive start and exclusive end",
-			query: search.NewQueryDataRange(now.Add(-30*day), now),
-			expected: []string{
-				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
-				"baz 10 days ago",
-				"nested 10 days ago",
-			},
+			name:     "transposition",
+			query:    search.NewQueryFuzzy("abr"),
+			expected: []string{"foo2", "foo1", "fit"},
 		},
 		{
-			name: "30 day range exclusive start and end",
+			name: "transposition disabled",
 			query: search.
-				NewQueryDataRange(now.Add(-30*day), now).
-				SetInclusiveStart(false).
-				SetInclusiveEnd(false),
-			expected: []string{
-				"bar 20 days ago", "bar 10 days ago",
-				"baz 10 days ago",
-				"nested 10 days ago",
-			},
+				NewQueryFuzzy("abr").
+				SetTranspositions(false),
+			expected: []string{},
 		},
 		{
-			name: "30 day range inclusive start and exclusive end",
+			name: "transposition disabled 1 off",
 			query: search.
-				NewQueryDataRange(now.Add(-30*day), now).
-				SetInclusiveEnd(false),
-			expected: []string{
-				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
-				"baz 10 days ago",
-				"nested 10 days ago",
-			},
+				NewQueryFuzzy("bax").
+				SetTranspositions(false),
+			expected: []string{"bar", "foo2", "foo1", "fit"},
 		},
 		{
-			name: "30 day range inclusive start and exclusive end",
+			name: "prefix",
 			query: search.
-				NewQueryDataRange(now.Add(-30*day), now).
-				SetInclusiveStart(true).
-				SetInclusiveEnd(false),
-			expected: []string{
-				"bar 20 days ago", "bar 30 days ago", "bar 10 days ago",
-				"baz 10 days ago",
-				"nested 10 days ago",
-			},
+				NewQueryFuzzy("bxt").
+				SetPrefix(1),
+			expected: []string{"nested bit", "fit"},
 		},
 		{
-			name: "nested to inclusive start and exclusive end",
+			name: "prefix mismatch",
 			query: search.
-				NewQueryDataRange(now.Add(-30*day), now).
-				SetField("nested.date"),
-			expected: []string{"nested 10 days ago"},
+				NewQueryFuzzy("bxt").
+				SetPrefix(2),
+			expected: []string{},
 		},
 		{
-			name: "nested to inclusive start and inclusive end",
+			name: "nested field",
 			query: search.
-				NewQueryDataRange(now.Add(-30*day), now).
-				SetField("nested.date").
-				SetInclusiveEnd(true),
-			expected: []string{"nested 10 days ago", "nested today"},
+				NewQueryFuzzy("bat").
+				SetField("nest.second"),
+			expected: []string{"nested bit"},
 		},
 	}
 
@@ -393,6 +1583,34 @@ func TestQueryMatch(t *testing.T, engineInitFn InitFn) {
 				SetFuzziness(1),
 			expected: []string{"baz"},
 		},
+		{
+			name: "and operator",
+			query: search.
+				NewQueryMatch("bar bug").
+				SetOperator(search.MatchQueryOperatorAnd),
+			expected: []string{"foo1"},
+		},
+		{
+			name: "min should percent",
+			query: search.
+				NewQueryMatch("bar bug fit foo").
+				SetMinShouldPercent(50),
+			expected: []string{"fit", "foo1"},
+		},
+		{
+			name: "min should percent rounds down",
+			query: search.
+				NewQueryMatch("bar bug fit foo").
+				SetMinShouldPercent(80),
+			expected: []string{"fit"},
+		},
+		{
+			name: "min should percent requires at least one",
+			query: search.
+				NewQueryMatch("bar bug fit foo").
+				SetMinShouldPercent(10),
+			expected: []string{"fit", "foo1", "foo2"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -539,6 +1757,264 @@ func TestQueryMatchPhrase(t *testing.T, engineInitFn InitFn) {
 	}
 }
 
+func TestQueryMoreLikeThis(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "go intro",
+			v:  map[string]interface{}{"text": "go is a programming language for concurrency and servers"},
+		},
+		{
+			id: "go channels",
+			v:  map[string]interface{}{"text": "channels in go make concurrency simple for servers"},
+		},
+		{
+			id: "rust intro",
+			v:  map[string]interface{}{"text": "rust is a programming language focused on memory safety"},
+		},
+		{
+			id: "cooking",
+			v:  map[string]interface{}{"text": "pasta recipes with tomato and basil, pasta all day"},
+		},
+	}...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name: "like document",
+			query: search.NewQueryMoreLikeThis("text").
+				AddIDs("go intro").
+				SetMinTermFreq(1),
+			expected: []string{"go channels", "rust intro"},
+		},
+		{
+			name: "like documents",
+			query: search.NewQueryMoreLikeThis("text").
+				AddIDs("go intro", "rust intro").
+				SetMinTermFreq(1),
+			expected: []string{"go channels"},
+		},
+		{
+			name: "like text",
+			query: search.NewQueryMoreLikeThis("text").
+				AddLike("basil and tomato soup").
+				SetMinTermFreq(1),
+			expected: []string{"cooking"},
+		},
+		{
+			name: "min term freq",
+			query: search.NewQueryMoreLikeThis("text").
+				AddLike("servers servers and memory"),
+			expected: []string{"go intro", "go channels"},
+		},
+		{
+			name: "max query terms",
+			query: search.NewQueryMoreLikeThis("text").
+				AddLike("rust rust rust go").
+				SetMinTermFreq(1).
+				SetMaxQueryTerms(1),
+			expected: []string{"rust intro"},
+		},
+		{
+			name: "stop words",
+			query: search.NewQueryMoreLikeThis("text").
+				AddIDs("go intro").
+				AddStopWords("programming", "language").
+				SetMinTermFreq(1),
+			expected: []string{"go channels"},
+		},
+		{
+			name: "no significant terms",
+			query: search.NewQueryMoreLikeThis("text").
+				AddLike("quantum physics").
+				SetMinTermFreq(1),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestQueryMultiMatch(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "in title",
+			v: map[string]interface{}{
+				"title": "golang search",
+				"body":  "a library written in go for indexing documents",
+			},
+		},
+		{
+			id: "in body",
+			v: map[string]interface{}{
+				"title": "indexing documents",
+				"body":  "golang search library",
+			},
+		},
+		{
+			id: "neither",
+			v: map[string]interface{}{
+				"title": "cooking",
+				"body":  "pasta recipes",
+			},
+		},
+	}...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:     "best fields title boosted",
+			query:    search.NewQueryMultiMatch("golang search", "title^3", "body"),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name:     "best fields body boosted",
+			query:    search.NewQueryMultiMatch("golang search", "title", "body^3"),
+			expected: []string{"in body", "in title"},
+		},
+		{
+			name: "best fields with and operator",
+			query: search.
+				NewQueryMultiMatch("golang library", "title^3", "body").
+				SetOperator(search.MatchQueryOperatorAnd),
+			expected: []string{"in body"},
+		},
+		{
+			name: "best fields with min should percent",
+			query: search.
+				NewQueryMultiMatch("golang library pasta", "title^3", "body").
+				SetMinShouldPercent(67),
+			expected: []string{"in body"},
+		},
+		{
+			name: "most fields with min should percent",
+			query: search.
+				NewQueryMultiMatch("golang library pasta", "title^3", "body").
+				SetType(search.MultiMatchMostFields).
+				SetMinShouldPercent(67),
+			expected: []string{"in body"},
+		},
+		{
+			name: "cross fields with min should percent",
+			query: search.
+				NewQueryMultiMatch("golang library pasta", "title^3", "body").
+				SetType(search.MultiMatchCrossFields).
+				SetMinShouldPercent(67),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name: "most fields",
+			query: search.
+				NewQueryMultiMatch("golang", "title^3").
+				AddFields("body").
+				SetType(search.MultiMatchMostFields),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name: "cross fields with and operator",
+			query: search.
+				NewQueryMultiMatch("golang library", "title^3", "body").
+				SetType(search.MultiMatchCrossFields).
+				SetOperator(search.MatchQueryOperatorAnd),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name: "phrase",
+			query: search.
+				NewQueryMultiMatch("golang search", "title^3", "body").
+				SetType(search.MultiMatchPhrase),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name: "phrase out of order",
+			query: search.
+				NewQueryMultiMatch("search golang", "title^3", "body").
+				SetType(search.MultiMatchPhrase),
+			expected: []string{},
+		},
+		{
+			name: "phrase prefix",
+			query: search.
+				NewQueryMultiMatch("golang sea", "title^3", "body").
+				SetType(search.MultiMatchPhrasePrefix),
+			expected: []string{"in title", "in body"},
+		},
+		{
+			name: "phrase prefix single field",
+			query: search.
+				NewQueryMultiMatch("search lib", "title^3", "body").
+				SetType(search.MultiMatchPhrasePrefix),
+			expected: []string{"in body"},
+		},
+		{
+			name:     "no matches",
+			query:    search.NewQueryMultiMatch("rust", "title^3", "body"),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+			assert.Equal(t, uint64(len(tt.expected)), result.Total)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("invalid field boost", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryMultiMatch("golang", "title^high"))
+		require.Error(t, err)
+	})
+}
+
 func TestQueryNumericRange(t *testing.T, engineInitFn InitFn) {
 	t.Helper()
 
@@ -838,6 +2314,68 @@ func TestQueryTermRange(t *testing.T, engineInitFn InitFn) {
 	}
 }
 
+func TestQueryTerms(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		expected []string
+	}{
+		{
+			name:     "no matches",
+			query:    search.NewQueryTerms("b", "ba"),
+			expected: []string{},
+		},
+		{
+			name:     "basic terms",
+			query:    search.NewQueryTerms("bar", "baz"),
+			expected: []string{"bar", "foo2", "foo1", "fit"},
+		},
+		{
+			name: "min should",
+			query: search.
+				NewQueryTerms("foo", "bar", "fit").
+				SetMinShould(2),
+			expected: []string{"fit"},
+		},
+		{
+			name: "min should exceeds matches",
+			query: search.
+				NewQueryTerms("bar", "bug", "baz").
+				SetMinShould(3),
+			expected: []string{},
+		},
+		{
+			name: "nested terms",
+			query: search.
+				NewQueryTerms("bit", "up").
+				SetField("nest.second"),
+			expected: []string{"nested bit"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
 func hasHitIDs(t *testing.T, hits []search.Hit, expected ...string) {
 	t.Helper()
 
@@ -853,6 +2391,14 @@ func hasHitIDs(t *testing.T, hits []search.Hit, expected ...string) {
 	}
 }
 
+func hitScores(hits []search.Hit) map[string]float64 {
+	scores := make(map[string]float64, len(hits))
+	for _, h := range hits {
+		scores[h.ID] = h.Score
+	}
+	return scores
+}
+
 func seedIndex(t *testing.T, engine search.Engine, indexName string, docs ...struct {
 	id string
 	v  interface{}
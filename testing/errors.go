@@ -0,0 +1,117 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	tests := []struct {
+		name     string
+		fn       func(ctx context.Context) error
+		expected error
+		op       string
+	}{
+		{
+			name: "unknown index search",
+			fn: func(ctx context.Context) error {
+				_, err := engine.Index("unknown").Search(ctx, search.NewQueryMatchAll())
+				return err
+			},
+			expected: search.ErrIndexNotFound,
+			op:       "index",
+		},
+		{
+			name: "unknown index index",
+			fn: func(ctx context.Context) error {
+				return engine.Index("unknown").Index(ctx, "foo", map[string]string{"foo": "bar"})
+			},
+			expected: search.ErrIndexNotFound,
+			op:       "index",
+		},
+		{
+			name: "invalid query",
+			fn: func(ctx context.Context) error {
+				_, err := engine.Index(indexName).Search(ctx, search.NewQueryMultiMatch("bar"))
+				return err
+			},
+			expected: search.ErrInvalidQuery,
+			op:       "search",
+		},
+		{
+			name: "invalid search after",
+			fn: func(ctx context.Context) error {
+				_, err := engine.Index(indexName).Search(ctx, search.NewQueryMatchAll(),
+					search.WithSort(search.SortByID()),
+					search.WithSearchAfter("foo", "bar"),
+				)
+				return err
+			},
+			expected: search.ErrInvalidQuery,
+			op:       "search",
+		},
+		{
+			name: "suggest on a field without completions",
+			fn: func(ctx context.Context) error {
+				_, err := engine.Index(indexName).Suggest(ctx, "foo1", "ba")
+				return err
+			},
+			expected: search.ErrInvalidQuery,
+			op:       "suggest",
+		},
+		{
+			name: "empty document id",
+			fn: func(ctx context.Context) error {
+				return engine.Index(indexName).Index(ctx, "", map[string]string{"foo": "bar"})
+			},
+			expected: search.ErrInvalidArgument,
+			op:       "index",
+		},
+		{
+			name: "empty document id in batch",
+			fn: func(ctx context.Context) error {
+				return engine.Index(indexName).IndexBatch(ctx, search.Document{Data: map[string]string{"foo": "bar"}})
+			},
+			expected: search.ErrInvalidArgument,
+			op:       "batch",
+		},
+		{
+			name: "more like this unknown document",
+			fn: func(ctx context.Context) error {
+				_, err := engine.Index(indexName).Search(ctx, search.NewQueryMoreLikeThis("foo1").AddIDs("unknown"))
+				return err
+			},
+			expected: search.ErrDocumentNotFound,
+			op:       "search",
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			err := tt.fn(ctx)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.expected), "unexpected error: %v", err)
+
+			var searchErr *search.Error
+			require.True(t, errors.As(err, &searchErr))
+			assert.Equal(t, tt.op, searchErr.Op)
+		}
+		t.Run(tt.name, fn)
+	}
+}
@@ -0,0 +1,91 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, productDocs...)
+
+	query := search.NewQueryBoolean().
+		AddMust(search.NewQueryMatch("phone").SetField("text")).
+		AddShould(search.NewQueryMatch("case").SetField("text"))
+
+	t.Run("not requested", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, result.Hits)
+		for _, h := range result.Hits {
+			assert.Nil(t, h.Explanation)
+		}
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query, search.WithExplain())
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "phone case", "cheap phone", "pricey phone")
+		for _, h := range result.Hits {
+			require.NotNil(t, h.Explanation)
+			assert.InDelta(t, h.Score, h.Explanation.Value, 1e-9)
+			assertExplanationTree(t, h.Explanation)
+		}
+
+		explanation := result.Hits[0].Explanation
+		require.Len(t, explanation.Children, 2)
+
+		var sum float64
+		for _, child := range explanation.Children {
+			sum += child.Value
+		}
+		assert.InDelta(t, explanation.Value, sum, 1e-9)
+	})
+
+	t.Run("rendered", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query, search.WithExplain(), search.WithSize(1))
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+
+		lines := strings.Split(strings.TrimSuffix(result.Hits[0].Explanation.String(), "\n"), "\n")
+		require.True(t, len(lines) > 2)
+		assert.False(t, strings.HasPrefix(lines[0], " "))
+		assert.Contains(t, lines[0], "sum of")
+
+		for _, line := range lines[1:] {
+			assert.True(t, strings.HasPrefix(line, "  "), "line not indented: %q", line)
+		}
+		assert.Contains(t, lines[1], "%)")
+		assert.Contains(t, result.Hits[0].Explanation.String(), "text:case")
+	})
+}
+
+func assertExplanationTree(t *testing.T, ex *search.Explanation) {
+	t.Helper()
+
+	for _, child := range ex.Children {
+		require.NotNil(t, child)
+		assertExplanationTree(t, child)
+	}
+}
@@ -0,0 +1,182 @@
+package testing
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryGeo(t *testing.T, engineInitFn SchemaInitFn) {
+	t.Helper()
+
+	schema := search.Schema{
+		Fields: []search.FieldMapping{
+			*search.NewFieldMapping("location", search.FieldTypeGeoPoint),
+			*search.NewFieldMapping("store.location", search.FieldTypeGeoPoint),
+			*search.NewFieldMapping("stores", search.FieldTypeGeoPoint),
+		},
+	}
+
+	engine, indexName, cleanup := engineInitFn(t, schema)
+	defer cleanup()
+
+	var (
+		sf      = search.NewGeoPoint(37.7749, -122.4194)
+		oakland = search.NewGeoPoint(37.8044, -122.2712)
+		la      = search.NewGeoPoint(34.0522, -118.2437)
+		nyc     = search.NewGeoPoint(40.7128, -74.0060)
+	)
+
+	docs := []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "nyc",
+			v:  map[string]interface{}{"location": nyc},
+		},
+		{
+			id: "la",
+			v:  map[string]interface{}{"location": la},
+		},
+		{
+			id: "oakland",
+			v: map[string]interface{}{
+				"location": map[string]interface{}{"lat": oakland.Lat, "lon": oakland.Lon},
+			},
+		},
+		{
+			id: "sf",
+			v:  map[string]interface{}{"location": sf},
+		},
+		{
+			id: "nested sf",
+			v: map[string]interface{}{
+				"store": map[string]interface{}{"location": sf},
+			},
+		},
+		{
+			id: "chain",
+			v: map[string]interface{}{
+				"stores": []search.GeoPoint{oakland, nyc},
+			},
+		},
+	}
+
+	seedIndex(t, engine, indexName, docs...)
+
+	byDistanceFromSF := search.WithSort(search.SortByGeoDistance("location", sf, "km"))
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		opts     []search.SearchOptFn
+		expected []string
+	}{
+		{
+			name: "distance",
+			query: search.
+				NewQueryGeoDistance(sf, "20km").
+				SetField("location"),
+			opts:     []search.SearchOptFn{byDistanceFromSF},
+			expected: []string{"sf", "oakland"},
+		},
+		{
+			name: "distance in miles",
+			query: search.
+				NewQueryGeoDistance(sf, "400mi").
+				SetField("location"),
+			opts:     []search.SearchOptFn{byDistanceFromSF},
+			expected: []string{"sf", "oakland", "la"},
+		},
+		{
+			name: "distance nested",
+			query: search.
+				NewQueryGeoDistance(oakland, "20km").
+				SetField("store.location"),
+			expected: []string{"nested sf"},
+		},
+		{
+			name: "bounding box",
+			query: search.
+				NewQueryGeoBoundingBox(search.NewGeoPoint(42, -125), search.NewGeoPoint(32, -114)).
+				SetField("location"),
+			opts:     []search.SearchOptFn{byDistanceFromSF},
+			expected: []string{"sf", "oakland", "la"},
+		},
+		{
+			name: "polygon",
+			query: search.
+				NewQueryGeoPolygon(
+					search.NewGeoPoint(42, -125),
+					search.NewGeoPoint(32, -125),
+					search.NewGeoPoint(42, -114),
+				).
+				SetField("location"),
+			opts:     []search.SearchOptFn{byDistanceFromSF},
+			expected: []string{"sf", "oakland"},
+		},
+		{
+			name: "polygon multi valued",
+			query: search.
+				NewQueryGeoPolygon(
+					search.NewGeoPoint(42, -125),
+					search.NewGeoPoint(32, -125),
+					search.NewGeoPoint(42, -114),
+				).
+				SetField("stores"),
+			expected: []string{"chain"},
+		},
+		{
+			name: "sort by distance descending",
+			query: search.
+				NewQueryGeoDistance(sf, "5000km").
+				SetField("location"),
+			opts: []search.SearchOptFn{
+				search.WithSort(search.SortByGeoDistance("location", sf, "km").SetDesc(true)),
+			},
+			expected: []string{"nyc", "la", "oakland", "sf"},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query, tt.opts...)
+			require.NoError(t, err)
+
+			hasHitIDs(t, result.Hits, tt.expected...)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("distance sort values", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.
+			Index(indexName).
+			Search(ctx, search.NewQueryGeoDistance(sf, "20km").SetField("location"), byDistanceFromSF)
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 2)
+
+		distances := make([]float64, 0, len(result.Hits))
+		for _, h := range result.Hits {
+			require.Len(t, h.Sort, 1)
+			d, err := strconv.ParseFloat(h.Sort[0], 64)
+			require.NoError(t, err)
+			distances = append(distances, d)
+		}
+		assert.InDelta(t, 0, distances[0], 0.1)
+		assert.InDelta(t, 13.3, distances[1], 1)
+	})
+}
@@ -0,0 +1,55 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupIndex(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	t.Run("known index", func(t *testing.T) {
+		index, err := engine.LookupIndex(indexName)
+		require.NoError(t, err)
+		assert.Equal(t, indexName, index.Name())
+	})
+
+	for _, name := range []string{"unknown", "", indexName + " "} {
+		t.Run("unknown index "+name, func(t *testing.T) {
+			index, err := engine.LookupIndex(name)
+			require.Error(t, err)
+			assert.Nil(t, index)
+			assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+
+			var searchErr *search.Error
+			require.True(t, errors.As(err, &searchErr))
+			assert.Equal(t, name, searchErr.Index)
+		})
+	}
+
+	t.Run("unknown index from Index", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		index := engine.Index("unknown")
+		assert.Equal(t, "unknown", index.Name())
+
+		_, err := index.Search(ctx, search.NewQueryMatchAll())
+		assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+
+		err = index.IndexBatch(ctx, search.Document{ID: "foo", Data: map[string]string{"foo": "bar"}})
+		assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+
+		_, err = index.SuggestTerms(ctx, "foo", "bar")
+		assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+	})
+}
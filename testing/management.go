@@ -0,0 +1,177 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexManagement(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	for _, name := range []string{"zeta", "alpha", "mu"} {
+		index, err := engine.CreateIndex(ctx, name, search.Schema{})
+		require.NoError(t, err)
+		assert.Equal(t, name, index.Name())
+	}
+
+	t.Run("indices sorted by name", func(t *testing.T) {
+		assert.Equal(t, sortedNames(indexName, "alpha", "mu", "zeta"), indexNames(engine.Indices()))
+	})
+
+	t.Run("created index is usable", func(t *testing.T) {
+		index, err := engine.LookupIndex("alpha")
+		require.NoError(t, err)
+
+		require.NoError(t, index.Index(ctx, "foo", map[string]string{"text": "bar"}))
+		result, err := index.Search(ctx, search.NewQueryMatch("bar").SetField("text"))
+		require.NoError(t, err)
+		hasHitIDs(t, result.Hits, "foo")
+	})
+
+	t.Run("create existing index", func(t *testing.T) {
+		_, err := engine.CreateIndex(ctx, "alpha", search.Schema{})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrIndexExists))
+	})
+
+	t.Run("close index", func(t *testing.T) {
+		index := engine.Index("mu")
+		require.NoError(t, engine.CloseIndex(ctx, "mu"))
+
+		_, err := engine.LookupIndex("mu")
+		assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+		assert.Equal(t, sortedNames(indexName, "alpha", "zeta"), indexNames(engine.Indices()))
+
+		_, err = index.Search(ctx, search.NewQueryMatchAll())
+		require.Error(t, err)
+	})
+
+	t.Run("close unknown index", func(t *testing.T) {
+		err := engine.CloseIndex(ctx, "unknown")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, search.ErrIndexNotFound))
+	})
+}
+
+// TestEngineConcurrency exercises the engine from many goroutines. Run it
+// with the race detector.
+func TestEngineConcurrency(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, simpleDocs...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*4)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(4)
+
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				id := fmt.Sprintf("worker %d doc %d", w, i)
+				if err := engine.Index(indexName).Index(ctx, id, map[string]string{"worker": "doc"}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if _, err := engine.Index(indexName).Search(ctx, search.NewQueryMatch("bar")); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+
+		go func(w int) {
+			defer wg.Done()
+			name := fmt.Sprintf("worker %d", w)
+			for i := 0; i < 5; i++ {
+				index, err := engine.CreateIndex(ctx, name, search.Schema{})
+				if err != nil {
+					errs <- err
+					return
+				}
+				if err := index.Index(ctx, "foo", map[string]string{"text": "bar"}); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := index.Search(ctx, search.NewQueryMatchAll()); err != nil {
+					errs <- err
+					return
+				}
+				if err := engine.CloseIndex(ctx, name); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(w)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				names := indexNames(engine.Indices())
+				if !sort.StringsAreSorted(names) {
+					errs <- fmt.Errorf("indices not sorted: %v", names)
+					return
+				}
+				if _, err := engine.LookupIndex(indexName); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	result, err := engine.
+		Index(indexName).
+		Search(ctx, search.NewQueryMatch("doc").SetField("worker"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(workers*20), result.Total)
+	assert.Equal(t, []string{indexName}, indexNames(engine.Indices()))
+}
+
+func indexNames(indices []search.Index) []string {
+	names := make([]string, 0, len(indices))
+	for _, index := range indices {
+		names = append(names, index.Name())
+	}
+	return names
+}
+
+func sortedNames(names ...string) []string {
+	sort.Strings(names)
+	return names
+}
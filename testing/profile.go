@@ -0,0 +1,121 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "phone",
+			v:  map[string]interface{}{"text": "red phone"},
+		},
+		{
+			id: "photo",
+			v:  map[string]interface{}{"text": "red photo"},
+		},
+		{
+			id: "phonograph",
+			v:  map[string]interface{}{"text": "blue phonograph"},
+		},
+		{
+			id: "laptop",
+			v:  map[string]interface{}{"text": "red laptop"},
+		},
+	}...)
+
+	query := search.NewQueryBoolean().
+		AddMust(search.NewQueryPrefix("pho").SetField("text")).
+		AddShould(search.NewQueryTerm("red").SetField("text"))
+
+	t.Run("not requested", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query)
+		require.NoError(t, err)
+
+		assert.Nil(t, result.Profile)
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query, search.WithProfile())
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "phone", "photo", "phonograph")
+
+		profile := result.Profile
+		require.NotNil(t, profile)
+		assert.True(t, profile.Conversion > 0)
+		assert.True(t, profile.Backend > 0)
+
+		root := profile.Query
+		require.NotNil(t, root)
+		require.NotEmpty(t, root.Children)
+		assert.True(t, root.DocsVisited >= result.Total)
+		assert.True(t, root.Time > 0)
+
+		prefix := findClauseProfile(root, "prefix")
+		require.NotNil(t, prefix, profile.String())
+		assert.Equal(t, uint64(3), prefix.TermsExpanded)
+		assert.Equal(t, uint64(3), prefix.DocsVisited)
+		assert.True(t, root.TermsExpanded >= prefix.TermsExpanded)
+		assert.True(t, root.Time >= prefix.Time)
+
+		assert.Contains(t, profile.String(), prefix.Description)
+	})
+
+	t.Run("collapsed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		result, err := engine.Index(indexName).Search(ctx, query,
+			search.WithProfile(),
+			search.WithCollapse(search.CollapseByField("text")),
+		)
+		require.NoError(t, err)
+
+		hasHitIDs(t, result.Hits, "phone", "photo", "phonograph")
+
+		profile := result.Profile
+		require.NotNil(t, profile)
+
+		// the profile covers a single execution of the query
+		prefix := findClauseProfile(profile.Query, "prefix")
+		require.NotNil(t, prefix, profile.String())
+		assert.Equal(t, uint64(3), prefix.TermsExpanded)
+		assert.Equal(t, uint64(3), prefix.DocsVisited)
+	})
+}
+
+// findClauseProfile returns the first clause of the profile whose
+// description starts with the prefix.
+func findClauseProfile(c *search.ClauseProfile, prefix string) *search.ClauseProfile {
+	if strings.HasPrefix(c.Description, prefix) {
+		return c
+	}
+	for _, child := range c.Children {
+		if found := findClauseProfile(child, prefix); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var spellDocs = []struct {
+	id string
+	v  interface{}
+}{
+	{
+		id: "fox",
+		v:  map[string]string{"text": "the quick brown fox"},
+	},
+	{
+		id: "dogs",
+		v:  map[string]string{"text": "quick brown dogs"},
+	},
+	{
+		id: "cat",
+		v:  map[string]string{"text": "quiet cat"},
+	},
+}
+
+func TestQueryDidYouMean(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, spellDocs...)
+
+	tests := []struct {
+		name     string
+		query    search.Query
+		opts     []search.SearchOptFn
+		expected string
+	}{
+		{
+			name:     "no hits",
+			query:    search.NewQueryMatch("brwn dgs").SetField("text"),
+			opts:     []search.SearchOptFn{search.WithDidYouMean(1)},
+			expected: "brown dogs",
+		},
+		{
+			name:     "few hits",
+			query:    search.NewQueryMatch("Quikc fox").SetField("text"),
+			opts:     []search.SearchOptFn{search.WithDidYouMean(2)},
+			expected: "quick fox",
+		},
+		{
+			name:     "match phrase",
+			query:    search.NewQueryMatchPhrase("quick brwn").SetField("text"),
+			opts:     []search.SearchOptFn{search.WithDidYouMean(1)},
+			expected: "quick brown",
+		},
+		{
+			name: "boolean",
+			query: search.NewQueryBoolean().
+				AddMust(search.NewQueryMatch("quikc").SetField("text")).
+				AddShould(search.NewQueryMatch("dgs").SetField("text")),
+			opts:     []search.SearchOptFn{search.WithDidYouMean(1)},
+			expected: "quick dogs",
+		},
+		{
+			name:  "enough hits",
+			query: search.NewQueryMatch("quikc brown").SetField("text"),
+			opts:  []search.SearchOptFn{search.WithDidYouMean(1)},
+		},
+		{
+			name:  "not requested",
+			query: search.NewQueryMatch("brwn dgs").SetField("text"),
+		},
+		{
+			name:  "no corrections",
+			query: search.NewQueryMatch("zebra").SetField("text"),
+			opts:  []search.SearchOptFn{search.WithDidYouMean(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			result, err := engine.
+				Index(indexName).
+				Search(ctx, tt.query, tt.opts...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, result.DidYouMean)
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestSuggestTerms(t *testing.T, engineInitFn InitFn) {
+	t.Helper()
+
+	engine, indexName, cleanup := engineInitFn(t)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, spellDocs...)
+
+	type correction struct {
+		term     string
+		distance int
+		docFreq  uint64
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		opts     []search.TermSuggestOptFn
+		expected map[string][]correction
+	}{
+		{
+			name: "ranked by distance",
+			text: "quikc",
+			expected: map[string][]correction{
+				"quikc": {{"quick", 1, 2}, {"quiet", 2, 1}},
+			},
+		},
+		{
+			name: "known terms are skipped",
+			text: "quick brwn",
+			expected: map[string][]correction{
+				"brwn": {{"brown", 1, 2}},
+			},
+		},
+		{
+			name: "size",
+			text: "quikc",
+			opts: []search.TermSuggestOptFn{search.WithTermSuggestSize(1)},
+			expected: map[string][]correction{
+				"quikc": {{"quick", 1, 2}},
+			},
+		},
+		{
+			name: "max edits",
+			text: "quikc",
+			opts: []search.TermSuggestOptFn{search.WithTermSuggestMaxEdits(1)},
+			expected: map[string][]correction{
+				"quikc": {{"quick", 1, 2}},
+			},
+		},
+		{
+			name: "prefix",
+			text: "wuick",
+			opts: []search.TermSuggestOptFn{search.WithTermSuggestPrefix(0)},
+			expected: map[string][]correction{
+				"wuick": {{"quick", 1, 2}},
+			},
+		},
+		{
+			name: "prefix mismatch",
+			text: "wuick",
+			expected: map[string][]correction{
+				"wuick": {},
+			},
+		},
+		{
+			name:     "no misspellings",
+			text:     "quick brown",
+			expected: map[string][]correction{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			suggestions, err := engine.
+				Index(indexName).
+				SuggestTerms(ctx, "text", tt.text, tt.opts...)
+			require.NoError(t, err)
+
+			actual := make(map[string][]correction)
+			for _, s := range suggestions {
+				corrections := make([]correction, 0, len(s.Corrections))
+				for _, c := range s.Corrections {
+					corrections = append(corrections, correction{c.Term, c.Distance, c.DocFreq})
+				}
+				actual[s.Term] = corrections
+			}
+			assert.Equal(t, tt.expected, actual)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("offsets", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		suggestions, err := engine.
+			Index(indexName).
+			SuggestTerms(ctx, "text", "quick brwn")
+		require.NoError(t, err)
+
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, 6, suggestions[0].Start)
+		assert.Equal(t, 10, suggestions[0].End)
+	})
+}
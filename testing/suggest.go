@@ -0,0 +1,174 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggest(t *testing.T, engineInitFn SchemaInitFn) {
+	t.Helper()
+
+	schema := search.Schema{
+		Fields: []search.FieldMapping{
+			*search.NewFieldMapping("title", search.FieldTypeCompletion).SetWeight("popularity"),
+			*search.NewFieldMapping("nest.name", search.FieldTypeCompletion),
+		},
+	}
+
+	engine, indexName, cleanup := engineInitFn(t, schema)
+	defer cleanup()
+
+	seedIndex(t, engine, indexName, []struct {
+		id string
+		v  interface{}
+	}{
+		{
+			id: "hello",
+			v: map[string]interface{}{
+				"title":      "Hello World",
+				"popularity": 10,
+				"category":   "greeting",
+			},
+		},
+		{
+			id: "help",
+			v: map[string]interface{}{
+				"title":      "Help Center",
+				"popularity": 50,
+				"category":   "support",
+			},
+		},
+		{
+			id: "helmet",
+			v: map[string]interface{}{
+				"title":      "Hélmet Store",
+				"popularity": 5,
+				"category":   "shop",
+			},
+		},
+		{
+			id: "news",
+			v: map[string]interface{}{
+				"title":      []string{"World News", "Hello Again"},
+				"popularity": 1,
+				"category":   "news",
+			},
+		},
+		{
+			id: "nested",
+			v: map[string]interface{}{
+				"nest": map[string]interface{}{"name": "Nested Helper"},
+			},
+		},
+	}...)
+
+	tests := []struct {
+		name     string
+		field    string
+		prefix   string
+		opts     []search.SuggestOptFn
+		expected []string
+	}{
+		{
+			name:     "ranked by weight",
+			field:    "title",
+			prefix:   "hel",
+			expected: []string{"Help Center", "Hello World", "Hélmet Store", "Hello Again"},
+		},
+		{
+			name:     "whole prefix",
+			field:    "title",
+			prefix:   "hello w",
+			expected: []string{"Hello World"},
+		},
+		{
+			name:     "case and accent insensitive",
+			field:    "title",
+			prefix:   "HELM",
+			expected: []string{"Hélmet Store"},
+		},
+		{
+			name:     "size",
+			field:    "title",
+			prefix:   "hel",
+			opts:     []search.SuggestOptFn{search.WithSuggestSize(2)},
+			expected: []string{"Help Center", "Hello World"},
+		},
+		{
+			name:     "fuzzy prefix",
+			field:    "title",
+			prefix:   "jelp",
+			opts:     []search.SuggestOptFn{search.WithSuggestFuzziness(1)},
+			expected: []string{"Help Center"},
+		},
+		{
+			name:   "context",
+			field:  "title",
+			prefix: "hel",
+			opts: []search.SuggestOptFn{
+				search.WithSuggestContext("category", "greeting", "news"),
+			},
+			expected: []string{"Hello World", "Hello Again"},
+		},
+		{
+			name:     "nested field",
+			field:    "nest.name",
+			prefix:   "nested h",
+			expected: []string{"Nested Helper"},
+		},
+		{
+			name:     "no completions",
+			field:    "title",
+			prefix:   "xyz",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			suggestions, err := engine.
+				Index(indexName).
+				Suggest(ctx, tt.field, tt.prefix, tt.opts...)
+			require.NoError(t, err)
+
+			texts := make([]string, 0, len(suggestions))
+			for _, s := range suggestions {
+				texts = append(texts, s.Text)
+			}
+			assert.Equal(t, tt.expected, texts)
+		}
+		t.Run(tt.name, fn)
+	}
+
+	t.Run("weights", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		suggestions, err := engine.
+			Index(indexName).
+			Suggest(ctx, "title", "help")
+		require.NoError(t, err)
+
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, "help", suggestions[0].ID)
+		assert.Equal(t, float64(50), suggestions[0].Weight)
+	})
+
+	t.Run("not a completion field", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, err := engine.
+			Index(indexName).
+			Suggest(ctx, "category", "gre")
+		require.Error(t, err)
+	})
+}